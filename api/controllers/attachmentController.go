@@ -0,0 +1,395 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/storage"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// Room for the multipart boundaries and headers on top of the file itself.
+	multipartOverhead = 1 << 20
+	multipartMemory   = 8 << 20
+)
+
+type AttachmentController struct {
+	attachmentService services.AttachmentService
+}
+
+func NewAttachmentController(attachmentService services.AttachmentService) *AttachmentController {
+	return &AttachmentController{attachmentService: attachmentService}
+}
+
+func (ac *AttachmentController) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, ac.attachmentService.MaxSize()+multipartOverhead)
+	err = r.ParseMultipartForm(multipartMemory)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.WriteJSONResponse(w, http.StatusRequestEntityTooLarge, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Attachment is too large.",
+				Errors:  services.ErrAttachmentTooLarge.Error(),
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to parse multipart form.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing file field.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	attachment, forbidden, err := ac.attachmentService.UploadAttachment(projectId, taskId, userId, header.Filename, file, header.Size)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "No task found for project.",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAttachmentTooLarge) {
+			utils.WriteJSONResponse(w, http.StatusRequestEntityTooLarge, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Attachment is too large.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAttachmentTypeNotAllowed) {
+			utils.WriteJSONResponse(w, http.StatusUnsupportedMediaType, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Attachment type is not allowed.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to upload attachment.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusCreated, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Attachment uploaded successfully.",
+		Data:    attachment,
+	})
+}
+
+func (ac *AttachmentController) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	attachments, forbidden, err := ac.attachmentService.GetAttachments(projectId, taskId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "No task found for project.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get attachments.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Attachments retrieved successfully.",
+		Data:    attachments,
+	})
+}
+
+func (ac *AttachmentController) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	attachmentIdStr, ok := vars["attachmentId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing attachmentId parameter.",
+		})
+		return
+	}
+	attachmentId, err := uuid.Parse(attachmentIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid attachmentId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	attachment, content, forbidden, err := ac.attachmentService.DownloadAttachment(projectId, taskId, attachmentId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, storage.ErrBlobNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Attachment not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to download attachment.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = io.Copy(w, content)
+	if err != nil {
+		log.Printf("Failed to stream attachment %s: %v", attachment.ID, err)
+	}
+}
+
+func (ac *AttachmentController) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	attachmentIdStr, ok := vars["attachmentId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing attachmentId parameter.",
+		})
+		return
+	}
+	attachmentId, err := uuid.Parse(attachmentIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid attachmentId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	forbidden, err := ac.attachmentService.DeleteAttachment(projectId, taskId, attachmentId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Attachment not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to delete attachment.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to delete this attachment.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Attachment deleted successfully.",
+	})
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type BurndownController struct {
+	burndownService services.BurndownService
+}
+
+func NewBurndownController(burndownService services.BurndownService) *BurndownController {
+	return &BurndownController{burndownService: burndownService}
+}
+
+func (bc *BurndownController) GetSprintBurndown(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	sprintIdStr, ok := vars["sprintId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing sprintId parameter.",
+		})
+		return
+	}
+	sprintId, err := uuid.Parse(sprintIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid sprintId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	burndown, forbidden, err := bc.burndownService.GetSprintBurndown(projectId, sprintId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrSprintNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Sprint not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get sprint burndown.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Sprint burndown retrieved successfully.",
+		Data:    burndown,
+	})
+}
+
+func (bc *BurndownController) GetMilestoneBurndown(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	milestoneIdStr, ok := vars["milestoneId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing milestoneId parameter.",
+		})
+		return
+	}
+	milestoneId, err := uuid.Parse(milestoneIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid milestoneId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	burndown, forbidden, err := bc.burndownService.GetMilestoneBurndown(projectId, milestoneId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrMilestoneNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Milestone not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get milestone burndown.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Milestone burndown retrieved successfully.",
+		Data:    burndown,
+	})
+}
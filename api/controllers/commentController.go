@@ -0,0 +1,376 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type CommentController struct {
+	commentService services.CommentService
+}
+
+func NewCommentController(commentService services.CommentService) *CommentController {
+	return &CommentController{commentService: commentService}
+}
+
+func (cc *CommentController) CreateComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.CommentDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	comment, forbidden, err := cc.commentService.CreateComment(projectId, taskId, userId, dto)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "No task found for project.",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidReplyParent) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid parent comment.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to create comment.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusCreated, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Comment created successfully.",
+		Data:    comment,
+	})
+}
+
+func (cc *CommentController) GetComments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	comments, forbidden, err := cc.commentService.GetComments(projectId, taskId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "No task found for project.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get comments.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Comments retrieved successfully.",
+		Data:    comments,
+	})
+}
+
+func (cc *CommentController) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	commentIdStr, ok := vars["commentId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing commentId parameter.",
+		})
+		return
+	}
+	commentId, err := uuid.Parse(commentIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid commentId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.CommentDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	comment, forbidden, err := cc.commentService.UpdateComment(projectId, taskId, commentId, userId, dto)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Comment not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to update comment.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to edit this comment.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Comment updated successfully.",
+		Data:    comment,
+	})
+}
+
+func (cc *CommentController) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	commentIdStr, ok := vars["commentId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing commentId parameter.",
+		})
+		return
+	}
+	commentId, err := uuid.Parse(commentIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid commentId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	forbidden, err := cc.commentService.DeleteComment(projectId, taskId, commentId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Comment not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to delete comment.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to delete this comment.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Comment deleted successfully.",
+	})
+}
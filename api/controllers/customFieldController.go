@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type CustomFieldController struct {
+	customFieldService services.CustomFieldService
+}
+
+func NewCustomFieldController(customFieldService services.CustomFieldService) *CustomFieldController {
+	return &CustomFieldController{customFieldService: customFieldService}
+}
+
+func (cfc *CustomFieldController) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.CreateCustomFieldDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	field, forbidden, err := cfc.customFieldService.CreateCustomField(projectId, userId, dto)
+	if err != nil {
+		if errors.Is(err, services.ErrCustomFieldOptions) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Validation failed.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrCustomFieldExists) {
+			utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Custom field already exists.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to create custom field.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to manage custom fields of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusCreated, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Custom field created successfully.",
+		Data:    field,
+	})
+}
+
+func (cfc *CustomFieldController) GetCustomFields(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	fields, forbidden, err := cfc.customFieldService.GetCustomFields(projectId, userId)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get custom fields.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Custom fields retrieved successfully.",
+		Data:    fields,
+	})
+}
+
+func (cfc *CustomFieldController) UpdateCustomField(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	fieldIdStr, ok := vars["fieldId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing fieldId parameter.",
+		})
+		return
+	}
+	fieldId, err := uuid.Parse(fieldIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid fieldId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.UpdateCustomFieldDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	field, forbidden, err := cfc.customFieldService.UpdateCustomField(projectId, fieldId, userId, dto)
+	if err != nil {
+		if errors.Is(err, services.ErrCustomFieldNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Custom field not found.",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrCustomFieldOptions) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Validation failed.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrCustomFieldExists) {
+			utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Custom field already exists.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to update custom field.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to manage custom fields of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Custom field updated successfully.",
+		Data:    field,
+	})
+}
+
+func (cfc *CustomFieldController) DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	fieldIdStr, ok := vars["fieldId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing fieldId parameter.",
+		})
+		return
+	}
+	fieldId, err := uuid.Parse(fieldIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid fieldId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	forbidden, err := cfc.customFieldService.DeleteCustomField(projectId, fieldId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrCustomFieldNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Custom field not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to delete custom field.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to manage custom fields of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Custom field deleted successfully.",
+	})
+}
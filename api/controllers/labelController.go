@@ -0,0 +1,490 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+type LabelController struct {
+	labelService services.LabelService
+}
+
+func NewLabelController(labelService services.LabelService) *LabelController {
+	return &LabelController{labelService: labelService}
+}
+
+func (lc *LabelController) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.LabelDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	label, forbidden, err := lc.labelService.CreateLabel(projectId, userId, dto)
+	if err != nil {
+		if errors.Is(err, services.ErrLabelExists) {
+			utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Label already exists.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to create label.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to manage labels of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusCreated, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Label created successfully.",
+		Data:    label,
+	})
+}
+
+func (lc *LabelController) GetLabels(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	labels, forbidden, err := lc.labelService.GetLabels(projectId, userId)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get labels.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Labels retrieved successfully.",
+		Data:    labels,
+	})
+}
+
+func (lc *LabelController) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	labelIdStr, ok := vars["labelId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing labelId parameter.",
+		})
+		return
+	}
+	labelId, err := uuid.Parse(labelIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid labelId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.LabelDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	label, forbidden, err := lc.labelService.UpdateLabel(projectId, labelId, userId, dto)
+	if err != nil {
+		if errors.Is(err, services.ErrLabelNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Label not found.",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrLabelExists) {
+			utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Label already exists.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to update label.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to manage labels of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Label updated successfully.",
+		Data:    label,
+	})
+}
+
+func (lc *LabelController) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	labelIdStr, ok := vars["labelId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing labelId parameter.",
+		})
+		return
+	}
+	labelId, err := uuid.Parse(labelIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid labelId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	forbidden, err := lc.labelService.DeleteLabel(projectId, labelId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrLabelNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Label not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to delete label.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to manage labels of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Label deleted successfully.",
+	})
+}
+
+func (lc *LabelController) AttachLabel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var dto *models.AttachLabelDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	task, forbidden, err := lc.labelService.AttachLabel(projectId, taskId, dto.LabelId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrLabelAlreadyAttached) {
+			utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Label is already attached to this task.",
+			})
+			return
+		}
+		writeTaskLabelError(w, err)
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Label attached successfully.",
+		Data:    task,
+	})
+}
+
+func (lc *LabelController) DetachLabel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	labelIdStr, ok := vars["labelId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing labelId parameter.",
+		})
+		return
+	}
+	labelId, err := uuid.Parse(labelIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid labelId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	task, forbidden, err := lc.labelService.DetachLabel(projectId, taskId, labelId, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrLabelNotAttached) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Label is not attached to this task.",
+			})
+			return
+		}
+		writeTaskLabelError(w, err)
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Label detached successfully.",
+		Data:    task,
+	})
+}
+
+func writeTaskLabelError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrLabelNotFound) {
+		utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Label not found.",
+		})
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+			Status:  false,
+			Message: "No task found for project.",
+		})
+		return
+	}
+	utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+		Status:  false,
+		Message: "Failed to update task labels.",
+		Errors:  err.Error(),
+	})
+}
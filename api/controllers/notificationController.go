@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+type NotificationController struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationController(notificationService services.NotificationService) *NotificationController {
+	return &NotificationController{notificationService: notificationService}
+}
+
+// GetNotifications lists the notifications of the user, only the unread ones with ?unread=true.
+func (nc *NotificationController) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+	var page = 0
+	pageParam := r.URL.Query().Get("page")
+	if pageParam != "" {
+		var err error
+		page, err = strconv.Atoi(pageParam)
+		if err != nil {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Wrong page param.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		page--
+		if page < 0 {
+			page = 0
+		}
+	}
+
+	var unreadOnly bool
+	unreadParam := r.URL.Query().Get("unread")
+	if unreadParam != "" {
+		var err error
+		unreadOnly, err = strconv.ParseBool(unreadParam)
+		if err != nil {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Wrong unread param.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+	}
+
+	notifications, err := nc.notificationService.GetNotifications(userId, unreadOnly, uint(page))
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get notifications.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Notifications retrieved successfully.",
+		Data:    notifications,
+	})
+}
+
+func (nc *NotificationController) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	unread, err := nc.notificationService.GetUnreadCount(userId)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to count unread notifications.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Unread notifications counted successfully.",
+		Data:    unread,
+	})
+}
+
+func (nc *NotificationController) MarkRead(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	notificationIdStr, ok := vars["notificationId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing notificationId parameter.",
+		})
+		return
+	}
+	notificationId, err := uuid.Parse(notificationIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid notificationId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	notification, err := nc.notificationService.MarkRead(userId, notificationId)
+	if err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Notification not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to mark notification read.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Notification marked read successfully.",
+		Data:    notification,
+	})
+}
+
+// MarkAllRead marks the notifications listed in the body read, or all of them when the body is empty
+// or lists none. It responds with the number of notifications left unread.
+func (nc *NotificationController) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	dto := &models.MarkNotificationsReadDTO{}
+	if r.ContentLength != 0 {
+		errorResponse := utils.UnmarshalRequest(r, &dto)
+		if errorResponse != nil {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+			return
+		}
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	unread, err := nc.notificationService.MarkAllRead(userId, dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to mark notifications read.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Notifications marked read successfully.",
+		Data:    unread,
+	})
+}
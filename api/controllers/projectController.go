@@ -47,6 +47,13 @@ func (pc *ProjectController) CreateProject(w http.ResponseWriter, r *http.Reques
 
 	project, err = pc.projectService.CreateProject(project, userId)
 	if err != nil {
+		if errors.Is(err, services.ErrProjectKeyExists) {
+			utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Project key is already in use.",
+			})
+			return
+		}
 		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
 			Status:  false,
 			Message: "Failed to create project.",
@@ -55,6 +62,7 @@ func (pc *ProjectController) CreateProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	w.Header().Set("ETag", utils.FormatETag(project.Version))
 	utils.WriteJSONResponse(w, http.StatusCreated, &utils.SuccessResponse{
 		Status:  true,
 		Message: "Project created successfully.",
@@ -147,6 +155,7 @@ func (pc *ProjectController) GetProjectById(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	w.Header().Set("ETag", utils.FormatETag(project.Version))
 	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
 		Status:  true,
 		Message: "Projects retrieved successfully.",
@@ -174,8 +183,14 @@ func (pc *ProjectController) UpdateProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	version, statusCode, errorResponse := utils.ReadIfMatch(r)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, statusCode, errorResponse)
+		return
+	}
+
 	var project *models.Project
-	errorResponse := utils.UnmarshalRequest(r, &project)
+	errorResponse = utils.UnmarshalRequest(r, &project)
 	if errorResponse != nil {
 		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
 		return
@@ -194,15 +209,66 @@ func (pc *ProjectController) UpdateProject(w http.ResponseWriter, r *http.Reques
 	project.ID = projectId
 	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
 
-	project, forbidden, err := pc.projectService.UpdateProject(project, userId)
+	project, forbidden, err := pc.projectService.UpdateProject(project, userId, version)
 	if err != nil {
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+		writeProjectUpdateError(w, project, err)
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
 			Status:  false,
-			Message: "Failed to update project.",
-			Errors:  err.Error(),
+			Message: "You are not the owner of this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(project.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Project updated successfully.",
+		Data:    project,
+	})
+}
+
+func (pc *ProjectController) PatchProject(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr, ok := vars["id"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing id parameter.",
+		})
+		return
+	}
+
+	projectId, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid id parameter.",
 		})
 		return
 	}
+
+	version, statusCode, errorResponse := utils.ReadIfMatch(r)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, statusCode, errorResponse)
+		return
+	}
+
+	patch, statusCode, errorResponse := utils.ReadMergePatch(r)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, statusCode, errorResponse)
+		return
+	}
+
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	project, forbidden, err := pc.projectService.PatchProject(projectId, userId, version, patch)
+	if err != nil {
+		writeProjectUpdateError(w, project, err)
+		return
+	}
 	if forbidden {
 		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
 			Status:  false,
@@ -211,6 +277,7 @@ func (pc *ProjectController) UpdateProject(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	w.Header().Set("ETag", utils.FormatETag(project.Version))
 	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
 		Status:  true,
 		Message: "Project updated successfully.",
@@ -263,3 +330,51 @@ func (pc *ProjectController) DeleteProject(w http.ResponseWriter, r *http.Reques
 		Message: "Project deleted successfully.",
 	})
 }
+
+func writeProjectUpdateError(w http.ResponseWriter, project *models.Project, err error) {
+	if errors.Is(err, services.ErrVersionConflict) {
+		w.Header().Set("ETag", utils.FormatETag(project.Version))
+		utils.WriteJSONResponse(w, http.StatusPreconditionFailed, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Project has been modified by someone else.",
+			Data:    project,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrProjectKeyExists) {
+		utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Project key is already in use.",
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrInvalidMergePatch) {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid merge patch.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  validationErr.Error(),
+		})
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Project not found.",
+		})
+		return
+	}
+	utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+		Status:  false,
+		Message: "Failed to update project.",
+		Errors:  err.Error(),
+	})
+}
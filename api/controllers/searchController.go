@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/drTragger/MykroTask/middleware"
+	"github.com/drTragger/MykroTask/services"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+type SearchController struct {
+	searchService services.SearchService
+}
+
+func NewSearchController(searchService services.SearchService) *SearchController {
+	return &SearchController{searchService: searchService}
+}
+
+func (sc *SearchController) Search(w http.ResponseWriter, r *http.Request) {
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+	var page = 0
+	pageParam := r.URL.Query().Get("page")
+	if pageParam != "" {
+		var err error
+		page, err = strconv.Atoi(pageParam)
+		if err != nil {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Wrong page param.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		page--
+		if page < 0 {
+			page = 0
+		}
+	}
+
+	results, err := sc.searchService.Search(userId, r.URL.Query().Get("q"), uint(page))
+	if err != nil {
+		if errors.Is(err, services.ErrEmptySearchQuery) {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Missing q parameter.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to search.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Search results retrieved successfully.",
+		Data:    results,
+	})
+}
@@ -64,6 +64,46 @@ func (tc *TaskController) CreateTask(w http.ResponseWriter, r *http.Request) {
 
 	task, forbidden, err := tc.taskService.CreateTask(task)
 	if err != nil {
+		if isTaskHierarchyError(err) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid parent task.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAssigneeNotMember) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid assignees.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCustomFieldValue) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid custom fields.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrMilestoneNotFound) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid milestone.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSprintNotFound) || errors.Is(err, services.ErrSprintClosed) {
+			utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid sprint.",
+				Errors:  err.Error(),
+			})
+			return
+		}
 		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
 			Status:  false,
 			Message: "Failed to create task.",
@@ -79,6 +119,7 @@ func (tc *TaskController) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
 	utils.WriteJSONResponse(w, http.StatusCreated, &utils.SuccessResponse{
 		Status:  true,
 		Message: "Task created successfully.",
@@ -128,8 +169,26 @@ func (tc *TaskController) GetTasksForUser(w http.ResponseWriter, r *http.Request
 
 	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
 
-	tasks, forbidden, err := tc.taskService.GetTasksForUser(projectId, memberId, userId)
+	filter, err := utils.ParseTaskFilter(r.URL.Query())
 	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid filter parameters.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	tasks, forbidden, err := tc.taskService.GetTasksForUser(projectId, userId, memberId, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskFilter) {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid filter parameters.",
+				Errors:  err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
 				Status:  false,
@@ -214,6 +273,51 @@ func (tc *TaskController) GetTaskById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Task retrieved successfully.",
+		Data:    task,
+	})
+}
+
+func (tc *TaskController) GetTaskByKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	key, ok := vars["key"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing key parameter.",
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	task, forbidden, err := tc.taskService.GetTaskByKey(key, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Task not found.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get task.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
 	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
 		Status:  true,
 		Message: "Task retrieved successfully.",
@@ -238,6 +342,7 @@ func (tc *TaskController) DeleteTask(w http.ResponseWriter, r *http.Request) {
 			Message: "Invalid projectId parameter.",
 			Errors:  err.Error(),
 		})
+		return
 	}
 	taskIdStr, ok := vars["taskId"]
 	if !ok {
@@ -258,8 +363,15 @@ func (tc *TaskController) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	}
 	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
 
-	forbidden, err := tc.taskService.DeleteTask(projectId, userId, taskId)
+	forbidden, err := tc.taskService.DeleteTask(projectId, taskId, userId)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "No task found for project.",
+			})
+			return
+		}
 		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
 			Status:  false,
 			Message: "Failed to delete task.",
@@ -302,8 +414,26 @@ func (tc *TaskController) GetTasksForProject(w http.ResponseWriter, r *http.Requ
 	}
 	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
 
-	tasks, forbidden, err := tc.taskService.GetTasksForProject(projectId, userId)
+	filter, err := utils.ParseTaskFilter(r.URL.Query())
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid filter parameters.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	tasks, forbidden, err := tc.taskService.GetTasksForProject(projectId, userId, filter)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskFilter) {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid filter parameters.",
+				Errors:  err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
 				Status:  false,
@@ -371,8 +501,14 @@ func (tc *TaskController) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	}
 	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
 
+	version, statusCode, errorResponse := utils.ReadIfMatch(r)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, statusCode, errorResponse)
+		return
+	}
+
 	var task *models.Task
-	errorResponse := utils.UnmarshalRequest(r, &task)
+	errorResponse = utils.UnmarshalRequest(r, &task)
 	if errorResponse != nil {
 		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
 		return
@@ -389,7 +525,137 @@ func (tc *TaskController) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, forbidden, err := tc.taskService.UpdateTask(projectId, taskId, userId, task)
+	task, forbidden, err := tc.taskService.UpdateTask(projectId, taskId, userId, version, task)
+	if err != nil {
+		writeTaskUpdateError(w, task, err)
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to edit this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Task updated successfully.",
+		Data:    task,
+	})
+}
+
+func (tc *TaskController) PatchTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	version, statusCode, errorResponse := utils.ReadIfMatch(r)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, statusCode, errorResponse)
+		return
+	}
+
+	patch, statusCode, errorResponse := utils.ReadMergePatch(r)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, statusCode, errorResponse)
+		return
+	}
+
+	task, forbidden, err := tc.taskService.PatchTask(projectId, taskId, userId, version, patch)
+	if err != nil {
+		writeTaskUpdateError(w, task, err)
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not allowed to edit this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Task updated successfully.",
+		Data:    task,
+	})
+}
+
+func (tc *TaskController) GetChildren(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	children, forbidden, err := tc.taskService.GetChildren(projectId, taskId, userId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
@@ -400,7 +666,7 @@ func (tc *TaskController) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		}
 		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
 			Status:  false,
-			Message: "Failed to update task.",
+			Message: "Failed to get subtasks.",
 			Errors:  err.Error(),
 		})
 		return
@@ -408,14 +674,328 @@ func (tc *TaskController) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	if forbidden {
 		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
 			Status:  false,
-			Message: "You are not allowed to edit this project.",
+			Message: "You are not a member of this project.",
 		})
 		return
 	}
 
 	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
 		Status:  true,
-		Message: "Task updated successfully.",
+		Message: "Subtasks retrieved successfully.",
+		Data:    children,
+	})
+}
+
+func (tc *TaskController) GetTaskTree(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	tree, forbidden, err := tc.taskService.GetTaskTree(projectId, taskId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+				Status:  false,
+				Message: "No task found for project.",
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get task tree.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Task tree retrieved successfully.",
+		Data:    tree,
+	})
+}
+
+func (tc *TaskController) MoveTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	taskIdStr, ok := vars["taskId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing taskId parameter.",
+		})
+		return
+	}
+	taskId, err := uuid.Parse(taskIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid taskId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	var dto *models.MoveTaskDTO
+	errorResponse := utils.UnmarshalRequest(r, &dto)
+	if errorResponse != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Validate the request data
+	err = utils.ValidateStruct(dto)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	task, forbidden, err := tc.taskService.MoveTask(projectId, taskId, userId, dto)
+	if err != nil {
+		writeTaskUpdateError(w, task, err)
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	w.Header().Set("ETag", utils.FormatETag(task.Version))
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Task moved successfully.",
 		Data:    task,
 	})
 }
+
+func (tc *TaskController) GetBoard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectIdStr, ok := vars["projectId"]
+	if !ok {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Missing projectId parameter.",
+		})
+		return
+	}
+	projectId, err := uuid.Parse(projectIdStr)
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid projectId parameter.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	userId := uuid.MustParse(r.Context().Value(middleware.UserIDKey).(string))
+
+	filter, err := utils.ParseTaskFilter(r.URL.Query())
+	if err != nil {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid filter parameters.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	board, forbidden, err := tc.taskService.GetBoard(projectId, userId, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskFilter) {
+			utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+				Status:  false,
+				Message: "Invalid filter parameters.",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Failed to get board.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if forbidden {
+		utils.WriteJSONResponse(w, http.StatusForbidden, &utils.ErrorResponse{
+			Status:  false,
+			Message: "You are not a member of this project.",
+		})
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, &utils.SuccessResponse{
+		Status:  true,
+		Message: "Board retrieved successfully.",
+		Data:    board,
+	})
+}
+
+func isTaskHierarchyError(err error) bool {
+	return errors.Is(err, services.ErrParentNotFound) ||
+		errors.Is(err, services.ErrTaskCycle) ||
+		errors.Is(err, services.ErrMaxDepthExceeded)
+}
+
+// writeTaskUpdateError maps the errors shared by the PUT and PATCH task endpoints to responses.
+func writeTaskUpdateError(w http.ResponseWriter, task *models.Task, err error) {
+	if errors.Is(err, services.ErrVersionConflict) {
+		w.Header().Set("ETag", utils.FormatETag(task.Version))
+		utils.WriteJSONResponse(w, http.StatusPreconditionFailed, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Task has been modified by someone else.",
+			Data:    task,
+		})
+		return
+	}
+	if errors.Is(err, utils.ErrInvalidMergePatch) {
+		utils.WriteJSONResponse(w, http.StatusBadRequest, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid merge patch.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Validation failed.",
+			Errors:  validationErr.Error(),
+		})
+		return
+	}
+	var blockersErr *services.UnfinishedBlockersError
+	if errors.As(err, &blockersErr) {
+		utils.WriteJSONResponse(w, http.StatusConflict, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Task is blocked by unfinished tasks.",
+			Errors:  blockersErr.Blockers,
+		})
+		return
+	}
+	if isTaskHierarchyError(err) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid parent task.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrAssigneeNotMember) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid assignees.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidCustomFieldValue) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid custom fields.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrMilestoneNotFound) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid milestone.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrSprintNotFound) || errors.Is(err, services.ErrSprintClosed) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid sprint.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidMove) {
+		utils.WriteJSONResponse(w, http.StatusUnprocessableEntity, &utils.ErrorResponse{
+			Status:  false,
+			Message: "Invalid move.",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteJSONResponse(w, http.StatusNotFound, &utils.ErrorResponse{
+			Status:  false,
+			Message: "No task found for project.",
+		})
+		return
+	}
+	utils.WriteJSONResponse(w, http.StatusInternalServerError, &utils.ErrorResponse{
+		Status:  false,
+		Message: "Failed to update task.",
+		Errors:  err.Error(),
+	})
+}
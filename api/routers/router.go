@@ -13,6 +13,21 @@ func SetupRouter(
 	projectController *controllers.ProjectController,
 	projectMemberController *controllers.ProjectMemberController,
 	taskController *controllers.TaskController,
+	taskDependencyController *controllers.TaskDependencyController,
+	commentController *controllers.CommentController,
+	attachmentController *controllers.AttachmentController,
+	taskActivityController *controllers.TaskActivityController,
+	labelController *controllers.LabelController,
+	customFieldController *controllers.CustomFieldController,
+	searchController *controllers.SearchController,
+	savedViewController *controllers.SavedViewController,
+	milestoneController *controllers.MilestoneController,
+	sprintController *controllers.SprintController,
+	burndownController *controllers.BurndownController,
+	workLogController *controllers.WorkLogController,
+	taskSeriesController *controllers.TaskSeriesController,
+	notificationController *controllers.NotificationController,
+	taskWatcherController *controllers.TaskWatcherController,
 	jwtKey []byte,
 ) http.Handler {
 	router := mux.NewRouter().StrictSlash(true)
@@ -20,8 +35,9 @@ func SetupRouter(
 	api.Use(middleware.JWTMiddleware(jwtKey))
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000"}, // Replace with your front-end URL
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "If-Match"},
+		ExposedHeaders:   []string{"ETag"},
 		AllowCredentials: true,
 	})
 	handler := c.Handler(router)
@@ -36,6 +52,7 @@ func SetupRouter(
 	api.HandleFunc("/projects", projectController.GetProjectsForUser).Methods(http.MethodGet)
 	api.HandleFunc("/projects/{id}", projectController.GetProjectById).Methods(http.MethodGet)
 	api.HandleFunc("/projects/{id}", projectController.UpdateProject).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{id}", projectController.PatchProject).Methods(http.MethodPatch)
 	api.HandleFunc("/projects/{id}", projectController.DeleteProject).Methods(http.MethodDelete)
 
 	// Project Members Management
@@ -50,6 +67,104 @@ func SetupRouter(
 	api.HandleFunc("/projects/{projectId}/tasks/{taskId}", taskController.DeleteTask).Methods(http.MethodDelete)
 	api.HandleFunc("/projects/{projectId}/tasks", taskController.GetTasksForProject).Methods(http.MethodGet)
 	api.HandleFunc("/projects/{projectId}/tasks/{taskId}", taskController.UpdateTask).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}", taskController.PatchTask).Methods(http.MethodPatch)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/move", taskController.MoveTask).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/board", taskController.GetBoard).Methods(http.MethodGet)
+	api.HandleFunc("/tasks/{key}", taskController.GetTaskByKey).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/children", taskController.GetChildren).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/tree", taskController.GetTaskTree).Methods(http.MethodGet)
+
+	// Labels
+	api.HandleFunc("/projects/{projectId}/labels", labelController.CreateLabel).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/labels", labelController.GetLabels).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/labels/{labelId}", labelController.UpdateLabel).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/labels/{labelId}", labelController.DeleteLabel).Methods(http.MethodDelete)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/labels", labelController.AttachLabel).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/labels/{labelId}", labelController.DetachLabel).Methods(http.MethodDelete)
+
+	// Custom Fields
+	api.HandleFunc("/projects/{projectId}/fields", customFieldController.CreateCustomField).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/fields", customFieldController.GetCustomFields).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/fields/{fieldId}", customFieldController.UpdateCustomField).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/fields/{fieldId}", customFieldController.DeleteCustomField).Methods(http.MethodDelete)
+
+	// Milestones
+	api.HandleFunc("/projects/{projectId}/milestones", milestoneController.CreateMilestone).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/milestones", milestoneController.GetMilestones).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/milestones/{milestoneId}", milestoneController.GetMilestone).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/milestones/{milestoneId}", milestoneController.UpdateMilestone).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/milestones/{milestoneId}", milestoneController.DeleteMilestone).Methods(http.MethodDelete)
+	api.HandleFunc("/projects/{projectId}/milestones/{milestoneId}/burndown", burndownController.GetMilestoneBurndown).Methods(http.MethodGet)
+
+	// Sprints
+	api.HandleFunc("/projects/{projectId}/sprints", sprintController.CreateSprint).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/sprints", sprintController.GetSprints).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/sprints/{sprintId}", sprintController.GetSprint).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/sprints/{sprintId}", sprintController.UpdateSprint).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/sprints/{sprintId}", sprintController.DeleteSprint).Methods(http.MethodDelete)
+	api.HandleFunc("/projects/{projectId}/sprints/{sprintId}/start", sprintController.StartSprint).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/sprints/{sprintId}/close", sprintController.CloseSprint).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/sprints/{sprintId}/burndown", burndownController.GetSprintBurndown).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/velocity", sprintController.GetVelocity).Methods(http.MethodGet)
+
+	// Saved Views
+	api.HandleFunc("/projects/{projectId}/views", savedViewController.CreateView).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/views", savedViewController.GetViews).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/views/{viewId}", savedViewController.GetView).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/views/{viewId}", savedViewController.UpdateView).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/views/{viewId}", savedViewController.DeleteView).Methods(http.MethodDelete)
+	api.HandleFunc("/projects/{projectId}/views/{viewId}/tasks", savedViewController.RunView).Methods(http.MethodGet)
+
+	// Task Dependencies
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/dependencies", taskDependencyController.CreateDependency).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/dependencies/{otherTaskId}", taskDependencyController.DeleteDependency).Methods(http.MethodDelete)
+
+	// Task Comments
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/comments", commentController.CreateComment).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/comments", commentController.GetComments).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/comments/{commentId}", commentController.UpdateComment).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/comments/{commentId}", commentController.DeleteComment).Methods(http.MethodDelete)
+
+	// Task Attachments
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/attachments", attachmentController.UploadAttachment).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/attachments", attachmentController.GetAttachments).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/attachments/{attachmentId}", attachmentController.DownloadAttachment).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/attachments/{attachmentId}", attachmentController.DeleteAttachment).Methods(http.MethodDelete)
+
+	// Task Activity
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/activity", taskActivityController.GetTaskActivities).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/activity", taskActivityController.GetProjectActivities).Methods(http.MethodGet)
+
+	// Work Logs
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/worklogs", workLogController.CreateWorkLog).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/worklogs", workLogController.GetWorkLogs).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/worklogs/{workLogId}", workLogController.UpdateWorkLog).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/worklogs/{workLogId}", workLogController.DeleteWorkLog).Methods(http.MethodDelete)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/timer", workLogController.StartTimer).Methods(http.MethodPost)
+	api.HandleFunc("/timer", workLogController.GetTimer).Methods(http.MethodGet)
+	api.HandleFunc("/timer/stop", workLogController.StopTimer).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/reports/time", workLogController.GetProjectTimeReport).Methods(http.MethodGet)
+	api.HandleFunc("/reports/time", workLogController.GetTimeReport).Methods(http.MethodGet)
+
+	// Task Watchers
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/watchers", taskWatcherController.GetWatchers).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/watch", taskWatcherController.Watch).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/watch", taskWatcherController.Unwatch).Methods(http.MethodDelete)
+
+	// Recurring Tasks
+	api.HandleFunc("/projects/{projectId}/tasks/{taskId}/recurrence", taskSeriesController.CreateSeries).Methods(http.MethodPost)
+	api.HandleFunc("/projects/{projectId}/series/{seriesId}", taskSeriesController.GetSeries).Methods(http.MethodGet)
+	api.HandleFunc("/projects/{projectId}/series/{seriesId}", taskSeriesController.UpdateSeries).Methods(http.MethodPut)
+	api.HandleFunc("/projects/{projectId}/series/{seriesId}", taskSeriesController.DeleteSeries).Methods(http.MethodDelete)
+
+	// Notifications
+	api.HandleFunc("/notifications", notificationController.GetNotifications).Methods(http.MethodGet)
+	api.HandleFunc("/notifications/unread-count", notificationController.GetUnreadCount).Methods(http.MethodGet)
+	api.HandleFunc("/notifications/read", notificationController.MarkAllRead).Methods(http.MethodPost)
+	api.HandleFunc("/notifications/{notificationId}/read", notificationController.MarkRead).Methods(http.MethodPost)
+
+	// Search
+	api.HandleFunc("/search", searchController.Search).Methods(http.MethodGet)
 
 	return handler
 }
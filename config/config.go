@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,35 @@ type Config struct {
 	DBHost     string
 	DBPort     string
 	JWTSecret  string
+
+	StorageDriver    string
+	StorageLocalPath string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKeyID    string
+	S3SecretKey      string
+
+	AttachmentMaxSize      int64
+	AttachmentAllowedTypes []string
+
+	SchedulerInterval  time.Duration
+	DueReminderOffsets []time.Duration
+}
+
+var defaultAttachmentAllowedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"text/csv",
+	"application/json",
+	"application/zip",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation",
 }
 
 func GetConfig() *Config {
@@ -29,5 +61,84 @@ func GetConfig() *Config {
 		DBHost:     os.Getenv("DB_HOST"),
 		DBPort:     os.Getenv("DB_PORT"),
 		JWTSecret:  os.Getenv("JWT_SECRET"),
+
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath: getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+		S3Endpoint:       os.Getenv("S3_ENDPOINT"),
+		S3Region:         os.Getenv("S3_REGION"),
+		S3Bucket:         os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:    os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretKey:      os.Getenv("S3_SECRET_ACCESS_KEY"),
+
+		AttachmentMaxSize:      getEnvInt64("ATTACHMENT_MAX_SIZE", 10<<20),
+		AttachmentAllowedTypes: getEnvList("ATTACHMENT_ALLOWED_TYPES", defaultAttachmentAllowedTypes),
+
+		SchedulerInterval:  getEnvDuration("SCHEDULER_INTERVAL", time.Minute),
+		DueReminderOffsets: getEnvDurationList("DUE_REMINDER_OFFSETS", []time.Duration{24 * time.Hour}),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %v", key, err)
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s value: %s", key, value)
+	}
+	return d
+}
+
+func getEnvDurationList(key string, fallback []time.Duration) []time.Duration {
+	var list []time.Duration
+	for _, item := range getEnvList(key, nil) {
+		d, err := time.ParseDuration(item)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid %s value: %s", key, item)
+		}
+		list = append(list, d)
+	}
+	if list == nil {
+		return fallback
+	}
+	return list
+}
+
+func getEnvList(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
 	}
+	return list
 }
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/drTragger/MykroTask/storage"
+)
+
+func InitBlobStore(cfg *Config) (storage.BlobStore, error) {
+	switch cfg.StorageDriver {
+	case "local":
+		log.Printf("Storing attachments in %s", cfg.StorageLocalPath)
+		return storage.NewLocalBlobStore(cfg.StorageLocalPath)
+	case "s3":
+		log.Printf("Storing attachments in bucket %s at %s", cfg.S3Bucket, cfg.S3Endpoint)
+		return storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
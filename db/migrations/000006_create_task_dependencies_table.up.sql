@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS task_dependencies
+(
+    blocker_id UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    blocked_id UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects (id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users (id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT chk_no_self_dependency CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_id ON task_dependencies (blocked_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS task_comments
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    task_id    UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    author_id  UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    parent_id  UUID REFERENCES task_comments (id) ON DELETE CASCADE,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id, created_at);
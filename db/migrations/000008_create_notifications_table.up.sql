@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type       VARCHAR(50) NOT NULL,
+    project_id UUID REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    comment_id UUID REFERENCES task_comments (id) ON DELETE CASCADE,
+    actor_id   UUID        REFERENCES users (id) ON DELETE SET NULL,
+    message    TEXT        NOT NULL,
+    read_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, created_at DESC);
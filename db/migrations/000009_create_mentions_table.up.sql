@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS mentions
+(
+    id           UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id   UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    task_id      UUID         NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    comment_id   UUID REFERENCES task_comments (id) ON DELETE CASCADE,
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    mentioned_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    handle       VARCHAR(100) NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_task_id ON mentions (task_id, comment_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_user_id ON mentions (user_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS task_attachments
+(
+    id           UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    task_id      UUID         NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    project_id   UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    uploaded_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    file_name    VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size         BIGINT       NOT NULL,
+    storage_key  TEXT         NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_attachments_task_id ON task_attachments (task_id);
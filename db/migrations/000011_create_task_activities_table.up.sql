@@ -0,0 +1,14 @@
+-- task_id has no foreign key on purpose: the history of a task outlives the task itself.
+CREATE TABLE IF NOT EXISTS task_activities
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    task_id    UUID        NOT NULL,
+    project_id UUID        NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    actor_id   UUID        REFERENCES users (id) ON DELETE SET NULL,
+    action     VARCHAR(20) NOT NULL,
+    changes    JSONB       NOT NULL     DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_activities_task_id ON task_activities (task_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_task_activities_project_id ON task_activities (project_id, created_at DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS version;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS version;
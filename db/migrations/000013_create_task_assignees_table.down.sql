@@ -0,0 +1,8 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS assignee UUID REFERENCES users (id);
+
+-- Only one assignee fits the old column, keep the one assigned first.
+UPDATE tasks AS t
+SET assignee = (SELECT a.user_id FROM task_assignees AS a WHERE a.task_id = t.id ORDER BY a.assigned_at, a.user_id LIMIT 1);
+
+DROP TABLE IF EXISTS task_assignees;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS task_assignees
+(
+    task_id     UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id     UUID REFERENCES users (id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees (user_id);
+
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, assignee
+FROM tasks
+WHERE assignee IS NOT NULL;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS assignee;
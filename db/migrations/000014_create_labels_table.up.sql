@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS labels
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id UUID        NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR(50) NOT NULL,
+    color      VARCHAR(7)  NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_project_id_name ON labels (project_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS task_labels
+(
+    task_id  UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    label_id UUID REFERENCES labels (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels (label_id);
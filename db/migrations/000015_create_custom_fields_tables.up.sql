@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS custom_fields
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    type       VARCHAR(20)  NOT NULL,
+    options    TEXT[]       NOT NULL    DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_fields_project_id_name ON custom_fields (project_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS task_custom_field_values
+(
+    task_id  UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    field_id UUID REFERENCES custom_fields (id) ON DELETE CASCADE,
+    value    JSONB NOT NULL,
+    PRIMARY KEY (task_id, field_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_custom_field_values_field_id ON task_custom_field_values (field_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_task_comments_search_vector;
+
+ALTER TABLE task_comments
+    DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,14 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+ALTER TABLE task_comments
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', body), 'C')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_search_vector ON task_comments USING GIN (search_vector);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS saved_views
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    owner_id   UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    filter     TEXT         NOT NULL    DEFAULT '',
+    columns    TEXT[]       NOT NULL    DEFAULT '{}',
+    shared     BOOLEAN      NOT NULL    DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_project_id ON saved_views (project_id, owner_id);
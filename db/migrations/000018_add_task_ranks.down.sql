@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_project_id_status_rank;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS rank;
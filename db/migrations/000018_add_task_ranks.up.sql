@@ -0,0 +1,15 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS rank VARCHAR(32) COLLATE "C";
+
+-- Existing tasks keep their creation order within each status column.
+UPDATE tasks AS t
+SET rank = r.rank
+FROM (SELECT id,
+             LPAD((ROW_NUMBER() OVER (PARTITION BY project_id, status ORDER BY created_at, id))::TEXT, 6, '0') || 'i' AS rank
+      FROM tasks) AS r
+WHERE t.id = r.id;
+
+ALTER TABLE tasks
+    ALTER COLUMN rank SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id_status_rank ON tasks (project_id, status, rank);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_tasks_project_id_number;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS number;
+
+DROP TABLE IF EXISTS project_keys;
+
+DROP INDEX IF EXISTS idx_projects_key;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS task_counter,
+    DROP COLUMN IF EXISTS key;
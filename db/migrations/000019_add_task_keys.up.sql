@@ -0,0 +1,45 @@
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS key          VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS task_counter INTEGER NOT NULL DEFAULT 0;
+
+-- Existing projects get a generated key that their owners can rename later.
+UPDATE projects AS p
+SET key = k.key
+FROM (SELECT id, 'P' || ROW_NUMBER() OVER (ORDER BY created_at, id) AS key FROM projects) AS k
+WHERE p.id = k.id;
+
+ALTER TABLE projects
+    ALTER COLUMN key SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_key ON projects (key);
+
+-- Every key a project has ever used, so task keys keep resolving after a rename.
+CREATE TABLE IF NOT EXISTS project_keys
+(
+    key        VARCHAR(10) PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_keys_project_id ON project_keys (project_id);
+
+INSERT INTO project_keys (key, project_id)
+SELECT key, id
+FROM projects
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS number INTEGER;
+
+UPDATE tasks AS t
+SET number = n.number
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY created_at, id) AS number FROM tasks) AS n
+WHERE t.id = n.id;
+
+ALTER TABLE tasks
+    ALTER COLUMN number SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_project_id_number ON tasks (project_id, number);
+
+UPDATE projects AS p
+SET task_counter = (SELECT COALESCE(MAX(t.number), 0) FROM tasks AS t WHERE t.project_id = p.id);
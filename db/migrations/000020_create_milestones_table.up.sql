@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS milestones
+(
+    id          UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id  UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL    DEFAULT '',
+    due_date    DATE         NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestones_project_id ON milestones (project_id);
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS milestone_id UUID REFERENCES milestones (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_milestone_id ON tasks (milestone_id);
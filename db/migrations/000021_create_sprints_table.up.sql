@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS sprints
+(
+    id                 UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id         UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name               VARCHAR(100) NOT NULL,
+    goal               TEXT         NOT NULL    DEFAULT '',
+    start_date         DATE         NOT NULL,
+    end_date           DATE         NOT NULL,
+    state              VARCHAR(20)  NOT NULL    DEFAULT 'planned',
+    committed_tasks    INTEGER,
+    completed_tasks    INTEGER,
+    carried_over_tasks INTEGER,
+    started_at         TIMESTAMP WITH TIME ZONE,
+    closed_at          TIMESTAMP WITH TIME ZONE,
+    created_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sprints_project_id ON sprints (project_id);
+
+-- A project runs at most one sprint at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_project_id_active ON sprints (project_id) WHERE state = 'active';
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS sprint_id UUID REFERENCES sprints (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_sprint_id ON tasks (sprint_id);
@@ -0,0 +1,10 @@
+ALTER TABLE sprints
+    DROP COLUMN IF EXISTS carried_over_estimate,
+    DROP COLUMN IF EXISTS completed_estimate,
+    DROP COLUMN IF EXISTS committed_estimate;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS estimate;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS estimate_unit;
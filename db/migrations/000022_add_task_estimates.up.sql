@@ -0,0 +1,11 @@
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS estimate_unit VARCHAR(10) NOT NULL DEFAULT 'points';
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS estimate NUMERIC(10, 2) CHECK (estimate >= 0);
+
+-- Sprint estimates are recorded next to the task counts when a sprint starts and closes.
+ALTER TABLE sprints
+    ADD COLUMN IF NOT EXISTS committed_estimate NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS completed_estimate NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS carried_over_estimate NUMERIC(12, 2);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS work_logs
+(
+    id         UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    task_id    UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    project_id UUID        NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Minutes spent, empty while the entry is a running timer.
+    duration   INTEGER CHECK (duration > 0),
+    note       TEXT        NOT NULL    DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_logs_task_id ON work_logs (task_id);
+CREATE INDEX IF NOT EXISTS idx_work_logs_project_id_started_at ON work_logs (project_id, started_at);
+
+-- A user runs at most one timer at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_work_logs_user_id_running ON work_logs (user_id) WHERE duration IS NULL;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS task_series
+(
+    id                 UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    project_id         UUID         NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    rule               VARCHAR(255) NOT NULL,
+    starts_at          TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- The occurrence the next instance is created for, empty once the rule has ended.
+    next_at            TIMESTAMP WITH TIME ZONE,
+    last_occurrence_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    occurrences        INTEGER      NOT NULL    DEFAULT 1,
+    title              VARCHAR(255) NOT NULL,
+    description        TEXT         NOT NULL    DEFAULT '',
+    status             VARCHAR(50)  NOT NULL,
+    priority           VARCHAR(50)  NOT NULL,
+    estimate           NUMERIC(10, 2),
+    assignees          UUID[]       NOT NULL    DEFAULT '{}',
+    created_by         UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_series_project_id ON task_series (project_id);
+CREATE INDEX IF NOT EXISTS idx_task_series_next_at ON task_series (next_at) WHERE next_at IS NOT NULL;
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS series_id     UUID REFERENCES task_series (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS occurrence_at TIMESTAMP WITH TIME ZONE;
+
+-- Each occurrence of a series gets one instance.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_series_id_occurrence_at ON tasks (series_id, occurrence_at);
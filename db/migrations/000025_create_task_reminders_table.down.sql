@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_due_date;
+
+DROP TABLE IF EXISTS task_reminders;
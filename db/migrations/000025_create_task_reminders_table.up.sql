@@ -0,0 +1,14 @@
+-- Reminders already sent about a due date. A reminder is claimed by inserting its row, so it goes out once
+-- however many servers look for it, and again only when the due date changes.
+CREATE TABLE IF NOT EXISTS task_reminders
+(
+    task_id        UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id        UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    due_date       DATE        NOT NULL,
+    kind           VARCHAR(20) NOT NULL,
+    offset_minutes INTEGER     NOT NULL DEFAULT 0,
+    sent_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id, due_date, kind, offset_minutes)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks (due_date) WHERE due_date IS NOT NULL;
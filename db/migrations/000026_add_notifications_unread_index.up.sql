@@ -0,0 +1,2 @@
+-- Serves the unread list and count of the notification center.
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_unread ON notifications (user_id, created_at DESC) WHERE read_at IS NULL;
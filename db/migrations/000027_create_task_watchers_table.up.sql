@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS task_watchers
+(
+    task_id    UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_watchers_user_id ON task_watchers (user_id);
+
+-- Creators and assignees of existing tasks watch them, like they do for new ones.
+INSERT INTO task_watchers (task_id, user_id)
+SELECT t.id, t.created_by FROM tasks AS t JOIN project_members AS pm ON pm.project_id = t.project_id AND pm.user_id = t.created_by
+UNION
+SELECT a.task_id, a.user_id FROM task_assignees AS a
+ON CONFLICT DO NOTHING;
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"github.com/drTragger/MykroTask/api/controllers"
 	"github.com/drTragger/MykroTask/api/routers"
 	"github.com/drTragger/MykroTask/config"
 	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/scheduler"
 	"github.com/drTragger/MykroTask/services"
 	"log"
 	"net/http"
+	"time"
 )
 
 func main() {
@@ -21,6 +24,12 @@ func main() {
 	}
 	defer db.Close()
 
+	// Initialize the attachment storage
+	blobStore, err := config.InitBlobStore(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	jwtKey := []byte(cfg.JWTSecret)
 
 	// Initialize repositories
@@ -28,21 +37,88 @@ func main() {
 	projectRepo := repository.NewProjectRepository(db)
 	projectMemberRepo := repository.NewProjectMemberRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
+	taskDependencyRepo := repository.NewTaskDependencyRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	mentionRepo := repository.NewMentionRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	taskActivityRepo := repository.NewTaskActivityRepository(db)
+	labelRepo := repository.NewLabelRepository(db)
+	customFieldRepo := repository.NewCustomFieldRepository(db)
+	milestoneRepo := repository.NewMilestoneRepository(db)
+	sprintRepo := repository.NewSprintRepository(db)
+	burndownRepo := repository.NewBurndownRepository(db)
+	workLogRepo := repository.NewWorkLogRepository(db)
+	taskSeriesRepo := repository.NewTaskSeriesRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	taskWatcherRepo := repository.NewTaskWatcherRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	savedViewRepo := repository.NewSavedViewRepository(db)
 
 	// Initialize services
 	userService := services.NewUserService(userRepo, jwtKey)
-	projectService := services.NewProjectService(projectRepo, projectMemberRepo, db)
-	projectMemberService := services.NewProjectMemberService(projectMemberRepo)
-	taskService := services.NewTaskService(taskRepo, projectMemberRepo)
+	projectService := services.NewProjectService(projectRepo, projectMemberRepo, attachmentRepo, blobStore, db)
+	notificationService := services.NewNotificationService(notificationRepo, projectMemberRepo, projectRepo)
+	projectMemberService := services.NewProjectMemberService(projectMemberRepo, taskRepo, taskActivityRepo, taskWatcherRepo, notificationService, db)
+	mentionService := services.NewMentionService(mentionRepo, projectMemberRepo, notificationService)
+	taskService := services.NewTaskService(taskRepo, projectMemberRepo, taskDependencyRepo, mentionRepo, taskActivityRepo, customFieldRepo, milestoneRepo, sprintRepo, taskSeriesRepo, taskWatcherRepo, attachmentRepo, blobStore, mentionService, notificationService, db)
+	taskDependencyService := services.NewTaskDependencyService(taskDependencyRepo, taskRepo, projectMemberRepo, db)
+	commentService := services.NewCommentService(commentRepo, taskRepo, projectMemberRepo, taskWatcherRepo, mentionService, notificationService, db)
+	taskActivityService := services.NewTaskActivityService(taskActivityRepo, projectMemberRepo)
+	labelService := services.NewLabelService(labelRepo, taskRepo, projectMemberRepo, taskActivityRepo, db)
+	customFieldService := services.NewCustomFieldService(customFieldRepo, projectMemberRepo)
+	milestoneService := services.NewMilestoneService(milestoneRepo, projectMemberRepo)
+	sprintService := services.NewSprintService(sprintRepo, taskRepo, projectRepo, projectMemberRepo, taskActivityRepo, db)
+	burndownService := services.NewBurndownService(burndownRepo, sprintRepo, milestoneRepo, projectMemberRepo)
+	workLogService := services.NewWorkLogService(workLogRepo, taskRepo, projectMemberRepo)
+	taskSeriesService := services.NewTaskSeriesService(taskSeriesRepo, taskRepo, projectMemberRepo, taskActivityRepo, taskWatcherRepo, notificationService, db)
+	taskWatcherService := services.NewTaskWatcherService(taskWatcherRepo, taskRepo, projectMemberRepo)
+	reminderService := services.NewReminderService(reminderRepo, notificationRepo, cfg.DueReminderOffsets, db)
+	searchService := services.NewSearchService(searchRepo)
+	savedViewService := services.NewSavedViewService(savedViewRepo, projectMemberRepo, taskService)
+	attachmentService := services.NewAttachmentService(attachmentRepo, taskRepo, projectMemberRepo, blobStore, cfg.AttachmentMaxSize, cfg.AttachmentAllowedTypes)
 
 	// Initialize controllers
 	userController := controllers.NewUserController(userService)
 	projectController := controllers.NewProjectController(projectService)
 	projectMemberController := controllers.NewProjectMemberController(projectMemberService)
 	taskController := controllers.NewTaskController(taskService)
+	taskDependencyController := controllers.NewTaskDependencyController(taskDependencyService)
+	commentController := controllers.NewCommentController(commentService)
+	attachmentController := controllers.NewAttachmentController(attachmentService)
+	taskActivityController := controllers.NewTaskActivityController(taskActivityService)
+	labelController := controllers.NewLabelController(labelService)
+	milestoneController := controllers.NewMilestoneController(milestoneService)
+	sprintController := controllers.NewSprintController(sprintService)
+	burndownController := controllers.NewBurndownController(burndownService)
+	workLogController := controllers.NewWorkLogController(workLogService)
+	taskSeriesController := controllers.NewTaskSeriesController(taskSeriesService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	taskWatcherController := controllers.NewTaskWatcherController(taskWatcherService)
+	customFieldController := controllers.NewCustomFieldController(customFieldService)
+	searchController := controllers.NewSearchController(searchService)
+	savedViewController := controllers.NewSavedViewController(savedViewService)
 
 	// Set up router
-	router := routers.SetupRouter(userController, projectController, projectMemberController, taskController, jwtKey)
+	router := routers.SetupRouter(userController, projectController, projectMemberController, taskController, taskDependencyController, commentController, attachmentController, taskActivityController, labelController, customFieldController, searchController, savedViewController, milestoneController, sprintController, burndownController, workLogController, taskSeriesController, notificationController, taskWatcherController, jwtKey)
+
+	// Start the background jobs
+	jobs := scheduler.New()
+	jobs.Every("recurring tasks", cfg.SchedulerInterval, func(ctx context.Context) error {
+		created, err := taskService.CreateDueInstances(time.Now())
+		if created > 0 {
+			log.Printf("Created %d recurring task(s)", created)
+		}
+		return err
+	})
+	jobs.Every("due date reminders", cfg.SchedulerInterval, func(ctx context.Context) error {
+		sent, err := reminderService.SendDueReminders(time.Now())
+		if sent > 0 {
+			log.Printf("Sent %d due date reminder(s)", sent)
+		}
+		return err
+	})
+	jobs.Start(context.Background())
 
 	log.Fatal(http.ListenAndServe(":8080", router))
 }
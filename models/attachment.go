@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Attachment struct {
+	ID          uuid.UUID  `json:"id"`
+	TaskId      uuid.UUID  `json:"taskId"`
+	ProjectId   uuid.UUID  `json:"projectId"`
+	UploadedBy  *uuid.UUID `json:"uploadedBy"`
+	FileName    string     `json:"fileName"`
+	ContentType string     `json:"contentType"`
+	Size        int64      `json:"size"`
+	StorageKey  string     `json:"-"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// BoardColumn holds the tasks of one status in board order.
+type BoardColumn struct {
+	Status string  `json:"status"`
+	Tasks  []*Task `json:"tasks"`
+}
+
+// MoveTaskDTO places a task in a status column right after AfterId and right before BeforeId.
+// Either neighbour may be left out; without both the task goes to the end of the column.
+type MoveTaskDTO struct {
+	Status   string     `json:"status" validate:"omitempty,min=1"`
+	AfterId  *uuid.UUID `json:"afterId"`
+	BeforeId *uuid.UUID `json:"beforeId"`
+}
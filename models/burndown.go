@@ -0,0 +1,34 @@
+package models
+
+import "github.com/google/uuid"
+
+// BurndownScope is what a burndown chart follows, the tasks of a sprint or of a milestone.
+type BurndownScope string
+
+const (
+	BurndownSprint    BurndownScope = "sprint"
+	BurndownMilestone BurndownScope = "milestone"
+)
+
+// BurndownPoint is the state of the scope at the end of a day, in tasks and in their estimates.
+// Ideal is the remaining work of a straight line from the scope of the first day down to zero on the last one.
+type BurndownPoint struct {
+	Date              string  `json:"date"`
+	Total             int     `json:"total"`
+	Completed         int     `json:"completed"`
+	Remaining         int     `json:"remaining"`
+	Ideal             float64 `json:"ideal"`
+	TotalEstimate     float64 `json:"totalEstimate"`
+	CompletedEstimate float64 `json:"completedEstimate"`
+	RemainingEstimate float64 `json:"remainingEstimate"`
+	IdealEstimate     float64 `json:"idealEstimate"`
+}
+
+// Burndown holds one point per day from the start date up to the end date or today, whichever comes first.
+type Burndown struct {
+	Scope     BurndownScope    `json:"scope"`
+	ScopeId   uuid.UUID        `json:"scopeId"`
+	StartDate string           `json:"startDate"`
+	EndDate   string           `json:"endDate"`
+	Points    []*BurndownPoint `json:"points"`
+}
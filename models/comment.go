@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Comment struct {
+	ID        uuid.UUID  `json:"id"`
+	TaskId    uuid.UUID  `json:"taskId"`
+	AuthorId  uuid.UUID  `json:"authorId"`
+	Author    *User      `json:"author,omitempty"`
+	ParentId  *uuid.UUID `json:"parentId"`
+	Body      string     `json:"body"`
+	Edited    bool       `json:"edited"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	Replies   []*Comment `json:"replies,omitempty"`
+
+	Mentions           []*Mention           `json:"mentions,omitempty"`
+	UnresolvedMentions []*UnresolvedMention `json:"unresolvedMentions,omitempty"`
+}
+
+// CommentDTO carries the markdown body of a comment and, for replies, the comment being replied to.
+type CommentDTO struct {
+	Body     string     `json:"body" validate:"required,max=10000"`
+	ParentId *uuid.UUID `json:"parentId"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldText         CustomFieldType = "text"
+	CustomFieldNumber       CustomFieldType = "number"
+	CustomFieldDate         CustomFieldType = "date"
+	CustomFieldSingleSelect CustomFieldType = "single_select"
+	CustomFieldMultiSelect  CustomFieldType = "multi_select"
+	CustomFieldUser         CustomFieldType = "user"
+)
+
+// MaxCustomFieldTextLength limits the length of text custom field values.
+const MaxCustomFieldTextLength = 1000
+
+func (t CustomFieldType) HasOptions() bool {
+	return t == CustomFieldSingleSelect || t == CustomFieldMultiSelect
+}
+
+// IsRanged tells whether values of the type can be filtered by a from/to range.
+func (t CustomFieldType) IsRanged() bool {
+	return t == CustomFieldNumber || t == CustomFieldDate
+}
+
+type CustomField struct {
+	ID        uuid.UUID       `json:"id"`
+	ProjectId uuid.UUID       `json:"projectId"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	Options   []string        `json:"options"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+func (f *CustomField) HasOption(option string) bool {
+	for _, o := range f.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateCustomFieldDTO struct {
+	Name    string          `json:"name" validate:"required,min=1,max=100"`
+	Type    CustomFieldType `json:"type" validate:"required,oneof=text number date single_select multi_select user"`
+	Options []string        `json:"options" validate:"unique,dive,required,max=100"`
+}
+
+// UpdateCustomFieldDTO leaves out the type, since existing values could not be converted.
+type UpdateCustomFieldDTO struct {
+	Name    string   `json:"name" validate:"required,min=1,max=100"`
+	Options []string `json:"options" validate:"unique,dive,required,max=100"`
+}
+
+// CustomFieldValues holds the custom field values of a task, keyed by field id.
+type CustomFieldValues map[uuid.UUID]json.RawMessage
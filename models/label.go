@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Label struct {
+	ID        uuid.UUID `json:"id"`
+	ProjectId uuid.UUID `json:"projectId"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	TaskCount int       `json:"taskCount"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type LabelDTO struct {
+	Name  string `json:"name" validate:"required,min=1,max=50"`
+	Color string `json:"color" validate:"required,hexcolor,len=7"`
+}
+
+type AttachLabelDTO struct {
+	LabelId uuid.UUID `json:"labelId" validate:"required"`
+}
+
+// LabelMatch tells whether a task has to carry any or all of the labels it is filtered by.
+type LabelMatch string
+
+const (
+	LabelMatchAny LabelMatch = "any"
+	LabelMatchAll LabelMatch = "all"
+)
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Mention struct {
+	ID          uuid.UUID  `json:"id"`
+	ProjectId   uuid.UUID  `json:"projectId"`
+	TaskId      uuid.UUID  `json:"taskId"`
+	CommentId   *uuid.UUID `json:"commentId"`
+	UserId      uuid.UUID  `json:"userId"`
+	MentionedBy *uuid.UUID `json:"mentionedBy"`
+	Handle      string     `json:"handle"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// UnresolvedMention is an @handle that did not match exactly one member of the project.
+type UnresolvedMention struct {
+	Handle string `json:"handle"`
+	Reason string `json:"reason"`
+}
+
+const (
+	MentionNotAMember = "not_a_member"
+	MentionAmbiguous  = "ambiguous"
+)
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Milestone struct {
+	ID          uuid.UUID     `json:"id"`
+	ProjectId   uuid.UUID     `json:"projectId"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	DueDate     time.Time     `json:"dueDate"`
+	Progress    *TaskProgress `json:"progress"`
+	Overdue     bool          `json:"overdue"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+// IsComplete reports whether the milestone has tasks and all of them are done.
+func (m *Milestone) IsComplete() bool {
+	return m.Progress != nil && m.Progress.Total > 0 && m.Progress.Done == m.Progress.Total
+}
+
+// IsOverdue reports whether the due date has passed before the milestone was completed.
+func (m *Milestone) IsOverdue(now time.Time) bool {
+	return !m.IsComplete() && m.DueDate.Format(DateLayout) < now.Format(DateLayout)
+}
+
+type MilestoneDTO struct {
+	Name        string    `json:"name" validate:"required,min=1,max=100"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"dueDate" validate:"required"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationMention      NotificationType = "mention"
+	NotificationAssigned     NotificationType = "assigned"
+	NotificationComment      NotificationType = "comment"
+	NotificationStatusChange NotificationType = "status_change"
+	NotificationTaskUpdated  NotificationType = "task_updated"
+	NotificationInvitation   NotificationType = "invitation"
+	NotificationDueSoon      NotificationType = "due_soon"
+	NotificationOverdue      NotificationType = "overdue"
+)
+
+func (t NotificationType) String() string {
+	return string(t)
+}
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	UserId    uuid.UUID        `json:"userId"`
+	Type      NotificationType `json:"type"`
+	ProjectId *uuid.UUID       `json:"projectId"`
+	TaskId    *uuid.UUID       `json:"taskId"`
+	CommentId *uuid.UUID       `json:"commentId"`
+	ActorId   *uuid.UUID       `json:"actorId"`
+	Message   string           `json:"message"`
+	ReadAt    *time.Time       `json:"readAt"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// MarkNotificationsReadDTO marks the given notifications of the user read, or all of them when IDs is empty.
+type MarkNotificationsReadDTO struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type UnreadNotifications struct {
+	Count int `json:"count"`
+}
@@ -2,16 +2,32 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"regexp"
 	"time"
 )
 
+// ProjectKeyPattern is the shape of a project key, the prefix of its task keys like WEB-123.
+var ProjectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// EstimateUnit is what task estimates of a project are counted in.
+type EstimateUnit string
+
+const (
+	EstimatePoints EstimateUnit = "points"
+	EstimateHours  EstimateUnit = "hours"
+)
+
 type Project struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name" validate:"required,min=3,max=255"`
-	Description string    `json:"description"`
-	StartDate   time.Time `json:"startDate"`
-	EndDate     time.Time `json:"endDate"`
-	OwnerId     uuid.UUID `json:"ownerId"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID           uuid.UUID     `json:"id"`
+	Name         string        `json:"name" validate:"required,min=3,max=255"`
+	Key          string        `json:"key" validate:"omitempty,projectkey"`
+	Description  string        `json:"description"`
+	EstimateUnit EstimateUnit  `json:"estimateUnit" validate:"omitempty,oneof=points hours"`
+	StartDate    time.Time     `json:"startDate"`
+	EndDate      *time.Time    `json:"endDate"`
+	OwnerId      uuid.UUID     `json:"ownerId"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	Version      int           `json:"version"`
+	Progress     *TaskProgress `json:"progress,omitempty"`
 }
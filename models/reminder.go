@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ReminderKind tells whether a due date reminder comes before the due date or after it has passed.
+type ReminderKind string
+
+const (
+	ReminderDueSoon ReminderKind = "due_soon"
+	ReminderOverdue ReminderKind = "overdue"
+)
+
+// DueReminder is a reminder about the due date of a task that was claimed for one of its assignees.
+// A task is due by the end of its due date in UTC.
+type DueReminder struct {
+	TaskId    uuid.UUID
+	ProjectId uuid.UUID
+	TaskKey   string
+	Title     string
+	DueDate   time.Time
+	UserId    uuid.UUID
+	Kind      ReminderKind
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// GetViewColumns lists the task fields a view can show, custom fields are added as "cf.<fieldId>".
+func GetViewColumns() []string {
+	return []string{
+		"title",
+		"description",
+		"status",
+		"priority",
+		"assignees",
+		"labels",
+		"dueDate",
+		"parentId",
+		"milestoneId",
+		"sprintId",
+		"estimate",
+		"progress",
+		"createdBy",
+		"createdAt",
+		"updatedAt",
+	}
+}
+
+// SavedView is a named task list. Filter holds the query string of the task list endpoint, sort included.
+// A view is private to its owner unless it is shared with the project.
+type SavedView struct {
+	ID        uuid.UUID `json:"id"`
+	ProjectId uuid.UUID `json:"projectId"`
+	OwnerId   uuid.UUID `json:"ownerId"`
+	Name      string    `json:"name"`
+	Filter    string    `json:"filter"`
+	Columns   []string  `json:"columns"`
+	Shared    bool      `json:"shared"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type SavedViewDTO struct {
+	Name    string   `json:"name" validate:"required,min=1,max=100"`
+	Filter  string   `json:"filter" validate:"max=2000"`
+	Columns []string `json:"columns" validate:"unique"`
+	Shared  bool     `json:"shared"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "github.com/google/uuid"
+
+// HighlightStart and HighlightStop wrap the matched words in search snippets.
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// SearchResult is a task matching a full-text search, either by itself or through one of its comments.
+// Snippets are HTML escaped with the matched words wrapped in <mark> tags.
+type SearchResult struct {
+	TaskId             uuid.UUID  `json:"taskId"`
+	ProjectId          uuid.UUID  `json:"projectId"`
+	Title              string     `json:"title"`
+	Status             string     `json:"status"`
+	Rank               float64    `json:"rank"`
+	TitleSnippet       string     `json:"titleSnippet"`
+	DescriptionSnippet string     `json:"descriptionSnippet"`
+	CommentId          *uuid.UUID `json:"commentId,omitempty"`
+	CommentSnippet     *string    `json:"commentSnippet,omitempty"`
+}
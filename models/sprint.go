@@ -0,0 +1,76 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type SprintState string
+
+const (
+	SprintPlanned SprintState = "planned"
+	SprintActive  SprintState = "active"
+	SprintClosed  SprintState = "closed"
+)
+
+// Sprint is a time box of a project. The committed count and estimate are recorded when it starts,
+// the completed and carried over ones when it closes.
+type Sprint struct {
+	ID                  uuid.UUID     `json:"id"`
+	ProjectId           uuid.UUID     `json:"projectId"`
+	Name                string        `json:"name"`
+	Goal                string        `json:"goal"`
+	StartDate           time.Time     `json:"startDate"`
+	EndDate             time.Time     `json:"endDate"`
+	State               SprintState   `json:"state"`
+	Progress            *TaskProgress `json:"progress"`
+	CommittedTasks      *int          `json:"committedTasks"`
+	CompletedTasks      *int          `json:"completedTasks"`
+	CarriedOverTasks    *int          `json:"carriedOverTasks"`
+	CommittedEstimate   *float64      `json:"committedEstimate"`
+	CompletedEstimate   *float64      `json:"completedEstimate"`
+	CarriedOverEstimate *float64      `json:"carriedOverEstimate"`
+	StartedAt           *time.Time    `json:"startedAt"`
+	ClosedAt            *time.Time    `json:"closedAt"`
+	CreatedAt           time.Time     `json:"createdAt"`
+	UpdatedAt           time.Time     `json:"updatedAt"`
+}
+
+func (s *Sprint) IsClosed() bool {
+	return s.State == SprintClosed
+}
+
+type SprintDTO struct {
+	Name      string    `json:"name" validate:"required,min=1,max=100"`
+	Goal      string    `json:"goal"`
+	StartDate time.Time `json:"startDate" validate:"required"`
+	EndDate   time.Time `json:"endDate" validate:"required,gtefield=StartDate"`
+}
+
+// CloseSprintDTO tells where the unfinished tasks of a closing sprint go, the backlog when NextSprintId is empty.
+type CloseSprintDTO struct {
+	NextSprintId *uuid.UUID `json:"nextSprintId"`
+}
+
+// SprintVelocity is what a closed sprint committed to and got done.
+type SprintVelocity struct {
+	SprintId          uuid.UUID `json:"sprintId"`
+	Name              string    `json:"name"`
+	StartDate         time.Time `json:"startDate"`
+	EndDate           time.Time `json:"endDate"`
+	CommittedTasks    int       `json:"committedTasks"`
+	CompletedTasks    int       `json:"completedTasks"`
+	CommittedEstimate float64   `json:"committedEstimate"`
+	CompletedEstimate float64   `json:"completedEstimate"`
+}
+
+// Velocity lists the closed sprints of a project, oldest first. Average is the completed estimate
+// of the last VelocitySprints closed sprints, the usual forecast for the next one.
+type Velocity struct {
+	Unit    EstimateUnit      `json:"unit"`
+	Sprints []*SprintVelocity `json:"sprints"`
+	Average float64           `json:"average"`
+}
+
+// VelocitySprints is how many of the latest closed sprints the average velocity is taken over.
+const VelocitySprints = 3
@@ -2,19 +2,124 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// MaxTaskDepth limits how deep a task hierarchy can go, e.g. epic -> story -> subtask.
+const MaxTaskDepth = 3
+
+func GetDoneStatuses() []string {
+	return []string{
+		"done",
+		"closed",
+		"completed",
+		"resolved",
+	}
+}
+
+func IsDoneStatus(status string) bool {
+	status = strings.ToLower(status)
+	for _, doneStatus := range GetDoneStatuses() {
+		if status == doneStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPriorities lists the well-known priorities from lowest to highest, used to sort tasks by priority.
+func GetPriorities() []string {
+	return []string{
+		"low",
+		"medium",
+		"high",
+		"urgent",
+	}
+}
+
 type Task struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title" validate:"required,min=1,max=255"`
-	Description string    `json:"description"`
-	Status      string    `json:"status" validate:"required"`
-	Priority    string    `json:"priority" validate:"required"`
-	Assignee    uuid.UUID `json:"assignee" validate:"required,uuid"`
-	DueDate     time.Time `json:"dueDate"`
-	ProjectID   uuid.UUID `json:"projectId"`
-	CreatedBy   uuid.UUID `json:"createdBy"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID           uuid.UUID         `json:"id"`
+	Key          string            `json:"key"`
+	Title        string            `json:"title" validate:"required,min=1,max=255"`
+	Description  string            `json:"description"`
+	Status       string            `json:"status" validate:"required"`
+	Priority     string            `json:"priority" validate:"required"`
+	Assignees    []uuid.UUID       `json:"assignees" validate:"unique"`
+	Labels       []uuid.UUID       `json:"labels"`
+	CustomFields CustomFieldValues `json:"customFields"`
+	DueDate      *time.Time        `json:"dueDate"`
+	ProjectID    uuid.UUID         `json:"projectId"`
+	ParentID     *uuid.UUID        `json:"parentId"`
+	MilestoneID  *uuid.UUID        `json:"milestoneId"`
+	SprintID     *uuid.UUID        `json:"sprintId"`
+	Estimate     *float64          `json:"estimate" validate:"omitempty,gte=0,lt=100000000"`
+	Rank         string            `json:"rank"`
+	SeriesID     *uuid.UUID        `json:"seriesId"`
+	OccurrenceAt *time.Time        `json:"occurrenceAt"`
+	CreatedBy    uuid.UUID         `json:"createdBy"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	Version      int               `json:"version"`
+	Progress     *TaskProgress     `json:"progress,omitempty"`
+	Children     []*Task           `json:"children,omitempty"`
+	BlockedBy    []*TaskLink       `json:"blockedBy,omitempty"`
+	Blocking     []*TaskLink       `json:"blocking,omitempty"`
+
+	Mentions           []*Mention           `json:"mentions,omitempty"`
+	UnresolvedMentions []*UnresolvedMention `json:"unresolvedMentions,omitempty"`
+}
+
+// ParseTaskKey splits a task key like WEB-123 into the project key and the task number.
+func ParseTaskKey(key string) (string, int, bool) {
+	i := strings.LastIndexByte(key, '-')
+	if i < 0 {
+		return "", 0, false
+	}
+	projectKey := strings.ToUpper(key[:i])
+	number, err := strconv.Atoi(key[i+1:])
+	if err != nil || number < 1 || !ProjectKeyPattern.MatchString(projectKey) {
+		return "", 0, false
+	}
+	return projectKey, number, true
+}
+
+func (t *Task) IsDone() bool {
+	return IsDoneStatus(t.Status)
+}
+
+// EstimateValue is the estimate of the task counted in rollups, tasks without one count as zero.
+func (t *Task) EstimateValue() float64 {
+	if t.Estimate == nil {
+		return 0
+	}
+	return *t.Estimate
+}
+
+// TaskProgress is the rollup of a group of tasks, like the direct children of a parent task.
+type TaskProgress struct {
+	Total    int             `json:"total"`
+	Done     int             `json:"done"`
+	Percent  float64         `json:"percent"`
+	Estimate *EstimateRollup `json:"estimate"`
+}
+
+func NewTaskProgress(total, done int, estimate, doneEstimate float64) *TaskProgress {
+	progress := &TaskProgress{Total: total, Done: done, Estimate: NewEstimateRollup(estimate, doneEstimate)}
+	if total > 0 {
+		progress.Percent = float64(done) * 100 / float64(total)
+	}
+	return progress
+}
+
+// EstimateRollup sums the estimates of a group of tasks, in the estimate unit of their project.
+type EstimateRollup struct {
+	Total     float64 `json:"total"`
+	Done      float64 `json:"done"`
+	Remaining float64 `json:"remaining"`
+}
+
+func NewEstimateRollup(total, done float64) *EstimateRollup {
+	return &EstimateRollup{Total: total, Done: done, Remaining: total - done}
 }
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ActivityAction string
+
+const (
+	ActivityCreated ActivityAction = "created"
+	ActivityUpdated ActivityAction = "updated"
+	ActivityDeleted ActivityAction = "deleted"
+)
+
+func (a ActivityAction) String() string {
+	return string(a)
+}
+
+// FieldChange holds the JSON encoded value of a field before and after a change.
+type FieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+type TaskActivity struct {
+	ID        uuid.UUID               `json:"id"`
+	TaskId    uuid.UUID               `json:"taskId"`
+	ProjectId uuid.UUID               `json:"projectId"`
+	ActorId   *uuid.UUID              `json:"actorId"`
+	Action    ActivityAction          `json:"action"`
+	Changes   map[string]*FieldChange `json:"changes"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+// auditFields lists the task fields tracked in the activity history, keyed by their JSON name.
+func (t *Task) auditFields() map[string]interface{} {
+	return map[string]interface{}{
+		"title":        t.Title,
+		"description":  t.Description,
+		"status":       t.Status,
+		"priority":     t.Priority,
+		"assignees":    t.Assignees,
+		"labels":       t.Labels,
+		"customFields": t.CustomFields,
+		"dueDate":      t.DueDate,
+		"parentId":     t.ParentID,
+		"milestoneId":  t.MilestoneID,
+		"sprintId":     t.SprintID,
+		"estimate":     t.Estimate,
+		"seriesId":     t.SeriesID,
+	}
+}
+
+// DiffTasks returns the tracked fields that differ between two versions of a task.
+// Pass a nil before for a newly created task and a nil after for a deleted one.
+func DiffTasks(before, after *Task) (map[string]*FieldChange, error) {
+	var beforeFields, afterFields map[string]interface{}
+	if before != nil {
+		beforeFields = before.auditFields()
+	}
+	if after != nil {
+		afterFields = after.auditFields()
+	}
+
+	names := make(map[string]bool)
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+
+	changes := make(map[string]*FieldChange)
+	for name := range names {
+		from, err := marshalAuditValue(beforeFields, name)
+		if err != nil {
+			return nil, err
+		}
+		to, err := marshalAuditValue(afterFields, name)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(from, to) {
+			changes[name] = &FieldChange{From: from, To: to}
+		}
+	}
+	return changes, nil
+}
+
+func marshalAuditValue(fields map[string]interface{}, name string) (json.RawMessage, error) {
+	value, ok := fields[name]
+	if !ok {
+		return json.RawMessage("null"), nil
+	}
+	return json.Marshal(value)
+}
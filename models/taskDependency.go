@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type DependencyType string
+
+const (
+	DependencyBlocks    DependencyType = "blocks"
+	DependencyBlockedBy DependencyType = "blocked_by"
+)
+
+type TaskDependency struct {
+	BlockerId uuid.UUID `json:"blockerId"`
+	BlockedId uuid.UUID `json:"blockedId"`
+	ProjectId uuid.UUID `json:"projectId"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateTaskDependencyDTO struct {
+	TaskId uuid.UUID      `json:"taskId" validate:"required"`
+	Type   DependencyType `json:"type" validate:"required,oneof=blocks blocked_by"`
+}
+
+// TaskLink is a short reference to a related task.
+type TaskLink struct {
+	ID     uuid.UUID `json:"id"`
+	Title  string    `json:"title"`
+	Status string    `json:"status"`
+}
+
+func (l *TaskLink) IsDone() bool {
+	return IsDoneStatus(l.Status)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CustomFieldOperator string
+
+const (
+	CustomFieldEquals CustomFieldOperator = "eq"
+	CustomFieldFrom   CustomFieldOperator = "from"
+	CustomFieldTo     CustomFieldOperator = "to"
+)
+
+// CustomFieldCondition matches tasks by the value of one custom field.
+// Field is resolved from FieldId by the service before the filter reaches the repository.
+type CustomFieldCondition struct {
+	FieldId  uuid.UUID
+	Operator CustomFieldOperator
+	Value    string
+	Field    *CustomField
+}
+
+type TaskSortKey string
+
+const (
+	SortByTitle       TaskSortKey = "title"
+	SortByStatus      TaskSortKey = "status"
+	SortByPriority    TaskSortKey = "priority"
+	SortByDueDate     TaskSortKey = "dueDate"
+	SortByCreatedAt   TaskSortKey = "createdAt"
+	SortByUpdatedAt   TaskSortKey = "updatedAt"
+	SortByRank        TaskSortKey = "rank"
+	SortByCustomField TaskSortKey = "customField"
+)
+
+func GetTaskSortKeys() []TaskSortKey {
+	return []TaskSortKey{
+		SortByTitle,
+		SortByStatus,
+		SortByPriority,
+		SortByDueDate,
+		SortByCreatedAt,
+		SortByUpdatedAt,
+		SortByRank,
+	}
+}
+
+// TaskSort is one key of a task list ordering.
+type TaskSort struct {
+	Key           TaskSortKey
+	CustomFieldId uuid.UUID
+	CustomField   *CustomField
+	Desc          bool
+}
+
+// DateLayout is the format of calendar dates in filters and date custom field values.
+const DateLayout = "2006-01-02"
+
+// DateRange holds inclusive calendar dates, either end may be left open.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+func (r DateRange) IsSet() bool {
+	return r.From != nil || r.To != nil
+}
+
+// TaskFilter narrows down the task list endpoints. Zero values leave a criterion out.
+type TaskFilter struct {
+	Statuses     []string
+	Priorities   []string
+	AssigneeIds  []uuid.UUID
+	Unassigned   bool
+	CreatorIds   []uuid.UUID
+	Due          DateRange
+	Overdue      bool
+	Created      DateRange
+	Updated      DateRange
+	Search       string
+	LabelIds     []uuid.UUID
+	LabelMatch   LabelMatch
+	MilestoneIds []uuid.UUID
+	NoMilestone  bool
+	SprintIds    []uuid.UUID
+	NoSprint     bool
+	CustomFields []*CustomFieldCondition
+	Sort         []*TaskSort
+}
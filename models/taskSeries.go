@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TaskSeries repeats a task by an RRULE. Every occurrence gets its own task, created from the template
+// of the series when the previous one is done or when the occurrence comes. NextAt is empty once the rule has ended.
+type TaskSeries struct {
+	ID               uuid.UUID   `json:"id"`
+	ProjectId        uuid.UUID   `json:"projectId"`
+	Rule             string      `json:"rule"`
+	StartsAt         time.Time   `json:"startsAt"`
+	NextAt           *time.Time  `json:"nextAt"`
+	LastOccurrenceAt time.Time   `json:"lastOccurrenceAt"`
+	Occurrences      int         `json:"occurrences"`
+	Title            string      `json:"title"`
+	Description      string      `json:"description"`
+	Status           string      `json:"status"`
+	Priority         string      `json:"priority"`
+	Estimate         *float64    `json:"estimate"`
+	Assignees        []uuid.UUID `json:"assignees"`
+	CreatedBy        uuid.UUID   `json:"createdBy"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	UpdatedAt        time.Time   `json:"updatedAt"`
+	Instances        []*Task     `json:"instances,omitempty"`
+}
+
+// RecurrenceDTO makes a task the first instance of a series.
+type RecurrenceDTO struct {
+	Rule string `json:"rule" validate:"required,max=255"`
+}
+
+// TaskSeriesDTO edits the whole series. Apart from the status, which only new instances start in,
+// the changes also apply to its open instances.
+type TaskSeriesDTO struct {
+	Rule        string      `json:"rule" validate:"required,max=255"`
+	Title       string      `json:"title" validate:"required,min=1,max=255"`
+	Description string      `json:"description"`
+	Status      string      `json:"status" validate:"required"`
+	Priority    string      `json:"priority" validate:"required"`
+	Estimate    *float64    `json:"estimate" validate:"omitempty,gte=0,lt=100000000"`
+	Assignees   []uuid.UUID `json:"assignees" validate:"unique"`
+}
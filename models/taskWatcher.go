@@ -0,0 +1,15 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TaskWatcher is a user who gets notified about the changes of a task. The creator and the assignees
+// of a task watch it automatically, anyone else in the project can choose to.
+type TaskWatcher struct {
+	TaskId    uuid.UUID `json:"taskId"`
+	UserId    uuid.UUID `json:"userId"`
+	User      *User     `json:"user,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+// WorkLog is time a user spent on a task. Duration is in minutes and empty while the entry is a running timer.
+type WorkLog struct {
+	ID        uuid.UUID `json:"id"`
+	TaskId    uuid.UUID `json:"taskId"`
+	ProjectId uuid.UUID `json:"projectId"`
+	UserId    uuid.UUID `json:"userId"`
+	User      *User     `json:"user,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	Duration  *int      `json:"duration"`
+	Note      string    `json:"note"`
+	Running   bool      `json:"running"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WorkLogDTO is a manual work log entry of at most a day.
+type WorkLogDTO struct {
+	StartedAt time.Time `json:"startedAt" validate:"required"`
+	Duration  int       `json:"duration" validate:"required,min=1,max=1440"`
+	Note      string    `json:"note" validate:"max=1000"`
+}
+
+type TimerDTO struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// TimeReportGroup is what the rows of a time report total up.
+type TimeReportGroup string
+
+const (
+	TimeByTask    TimeReportGroup = "task"
+	TimeByMember  TimeReportGroup = "member"
+	TimeByProject TimeReportGroup = "project"
+)
+
+// TimeReportQuery selects the work logs of a time report. The dates are inclusive and taken in UTC.
+type TimeReportQuery struct {
+	GroupBy TimeReportGroup
+	Range   DateRange
+}
+
+// TimeReportRow is the time logged on one task, by one member or in one project. Key is the task key
+// for task rows and empty otherwise.
+type TimeReportRow struct {
+	ID       uuid.UUID `json:"id"`
+	Key      string    `json:"key,omitempty"`
+	Name     string    `json:"name"`
+	Duration int       `json:"duration"`
+	Hours    float64   `json:"hours"`
+}
+
+// TimeReport totals the finished work logs of a date range, running timers are left out.
+type TimeReport struct {
+	GroupBy  TimeReportGroup  `json:"groupBy"`
+	From     *string          `json:"from"`
+	To       *string          `json:"to"`
+	Rows     []*TimeReportRow `json:"rows"`
+	Duration int              `json:"duration"`
+	Hours    float64          `json:"hours"`
+}
+
+// MinutesToHours converts logged minutes to hours rounded to two decimals, as they are billed.
+func MinutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
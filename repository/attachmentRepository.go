@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+const attachmentColumns = `id, task_id, project_id, uploaded_by, file_name, content_type, size, storage_key, created_at`
+
+func scanAttachment(row rowScanner, a *models.Attachment) error {
+	return row.Scan(&a.ID, &a.TaskId, &a.ProjectId, &a.UploadedBy, &a.FileName, &a.ContentType, &a.Size, &a.StorageKey, &a.CreatedAt)
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *models.Attachment) (*models.Attachment, error)
+	GetAttachments(taskId uuid.UUID) ([]*models.Attachment, error)
+	GetAttachmentById(taskId, attachmentId uuid.UUID) (*models.Attachment, error)
+	DeleteAttachment(taskId, attachmentId uuid.UUID) error
+	GetStorageKeysForTaskTx(tx *sql.Tx, taskId uuid.UUID) ([]string, error)
+	GetStorageKeysForProject(projectId uuid.UUID) ([]string, error)
+}
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) CreateAttachment(attachment *models.Attachment) (*models.Attachment, error) {
+	query := `INSERT INTO task_attachments (id, task_id, project_id, uploaded_by, file_name, content_type, size, storage_key) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + attachmentColumns + `;`
+	row := r.db.QueryRow(query, attachment.ID, attachment.TaskId, attachment.ProjectId, attachment.UploadedBy, attachment.FileName, attachment.ContentType, attachment.Size, attachment.StorageKey)
+
+	err := scanAttachment(row, attachment)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (r *attachmentRepository) GetAttachments(taskId uuid.UUID) ([]*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE task_id = $1 ORDER BY created_at;`
+	rows, err := r.db.Query(query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		var a models.Attachment
+		err = scanAttachment(rows, &a)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, &a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *attachmentRepository) GetAttachmentById(taskId, attachmentId uuid.UUID) (*models.Attachment, error) {
+	var a models.Attachment
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE id = $1 AND task_id = $2;`
+
+	err := scanAttachment(r.db.QueryRow(query, attachmentId, taskId), &a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(taskId, attachmentId uuid.UUID) error {
+	query := `DELETE FROM task_attachments WHERE id = $1 AND task_id = $2;`
+	_, err := r.db.Exec(query, attachmentId, taskId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetStorageKeysForTaskTx returns the blob keys of the attachments of a task, to clean them up once the task is deleted.
+func (r *attachmentRepository) GetStorageKeysForTaskTx(tx *sql.Tx, taskId uuid.UUID) ([]string, error) {
+	query := `SELECT storage_key FROM task_attachments WHERE task_id = $1;`
+	rows, err := tx.Query(query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanStorageKeys(rows)
+}
+
+// GetStorageKeysForProject returns the blob keys of all attachments in a project, to clean them up once it is deleted.
+func (r *attachmentRepository) GetStorageKeysForProject(projectId uuid.UUID) ([]string, error) {
+	query := `SELECT storage_key FROM task_attachments WHERE project_id = $1;`
+	rows, err := r.db.Query(query, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanStorageKeys(rows)
+}
+
+func scanStorageKeys(rows *sql.Rows) ([]string, error) {
+	var keys []string
+	for rows.Next() {
+		var key string
+		err := rows.Scan(&key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+// burndownScopes maps a burndown scope to the task column holding it and its name in the activity history.
+var burndownScopes = map[models.BurndownScope]struct {
+	column string
+	field  string
+}{
+	models.BurndownSprint:    {column: "sprint_id", field: "sprintId"},
+	models.BurndownMilestone: {column: "milestone_id", field: "milestoneId"},
+}
+
+type BurndownRepository interface {
+	GetScopeTasks(projectId uuid.UUID, scope models.BurndownScope, scopeId uuid.UUID) ([]*models.Task, error)
+	GetScopeActivities(projectId uuid.UUID, scope models.BurndownScope, scopeId uuid.UUID) ([]*models.TaskActivity, error)
+}
+
+type burndownRepository struct {
+	db *sql.DB
+}
+
+func NewBurndownRepository(db *sql.DB) BurndownRepository {
+	return &burndownRepository{db: db}
+}
+
+// GetScopeTasks returns the current state of the existing tasks that are or have ever been in the scope.
+func (r *burndownRepository) GetScopeTasks(projectId uuid.UUID, scope models.BurndownScope, scopeId uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.project_id = $1 AND (t.` + burndownScopes[scope].column + ` = $2 OR t.id IN (` + scopeHistory(scope) + `));`
+	rows, err := r.db.Query(query, projectId, scopeId, scopeId.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// GetScopeActivities returns the history of every task that is or has ever been in the scope, deleted ones included,
+// oldest first.
+func (r *burndownRepository) GetScopeActivities(projectId uuid.UUID, scope models.BurndownScope, scopeId uuid.UUID) ([]*models.TaskActivity, error) {
+	query := `SELECT ` + taskActivityColumns + ` FROM task_activities WHERE project_id = $1 AND task_id IN (
+                  SELECT t.id FROM tasks AS t WHERE t.` + burndownScopes[scope].column + ` = $2 UNION ` + scopeHistory(scope) + `
+              ) ORDER BY created_at, id;`
+	rows, err := r.db.Query(query, projectId, scopeId, scopeId.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []*models.TaskActivity
+	for rows.Next() {
+		var a models.TaskActivity
+		err = scanTaskActivity(rows, &a)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, &a)
+	}
+	return activities, rows.Err()
+}
+
+// scopeHistory selects the tasks whose history moved them into or out of the scope given as $3.
+func scopeHistory(scope models.BurndownScope) string {
+	field := burndownScopes[scope].field
+	return `SELECT a.task_id FROM task_activities AS a WHERE a.project_id = $1 AND (a.changes -> '` + field + `' ->> 'from' = $3 OR a.changes -> '` + field + `' ->> 'to' = $3)`
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+const commentColumns = `c.id, c.task_id, c.author_id, c.parent_id, c.body, c.created_at, c.updated_at, u.id, u.name, u.email`
+
+func scanComment(row rowScanner, c *models.Comment) error {
+	c.Author = &models.User{}
+	err := row.Scan(&c.ID, &c.TaskId, &c.AuthorId, &c.ParentId, &c.Body, &c.CreatedAt, &c.UpdatedAt, &c.Author.ID, &c.Author.Name, &c.Author.Email)
+	if err != nil {
+		return err
+	}
+	c.Edited = c.UpdatedAt.After(c.CreatedAt)
+	return nil
+}
+
+type CommentRepository interface {
+	CreateCommentTx(tx *sql.Tx, comment *models.Comment) (*models.Comment, error)
+	GetComments(taskId uuid.UUID) ([]*models.Comment, error)
+	GetCommentById(taskId, commentId uuid.UUID) (*models.Comment, error)
+	UpdateCommentTx(tx *sql.Tx, comment *models.Comment) (*models.Comment, error)
+	DeleteComment(taskId, commentId uuid.UUID) error
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) CreateCommentTx(tx *sql.Tx, comment *models.Comment) (*models.Comment, error) {
+	query := `WITH c AS (
+                  INSERT INTO task_comments (id, task_id, author_id, parent_id, body) VALUES ($1, $2, $3, $4, $5) RETURNING *
+              )
+              SELECT ` + commentColumns + ` FROM c JOIN users AS u ON c.author_id = u.id;`
+	row := tx.QueryRow(query, comment.ID, comment.TaskId, comment.AuthorId, comment.ParentId, comment.Body)
+
+	err := scanComment(row, comment)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (r *commentRepository) GetComments(taskId uuid.UUID) ([]*models.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM task_comments AS c JOIN users AS u ON c.author_id = u.id WHERE c.task_id = $1 ORDER BY c.created_at;`
+	rows, err := r.db.Query(query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		var c models.Comment
+		err = scanComment(rows, &c)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, &c)
+	}
+	return comments, rows.Err()
+}
+
+func (r *commentRepository) GetCommentById(taskId, commentId uuid.UUID) (*models.Comment, error) {
+	var c models.Comment
+	query := `SELECT ` + commentColumns + ` FROM task_comments AS c JOIN users AS u ON c.author_id = u.id WHERE c.id = $1 AND c.task_id = $2;`
+
+	err := scanComment(r.db.QueryRow(query, commentId, taskId), &c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *commentRepository) UpdateCommentTx(tx *sql.Tx, comment *models.Comment) (*models.Comment, error) {
+	query := `WITH c AS (
+                  UPDATE task_comments SET body = $3, updated_at = $4 WHERE id = $1 AND task_id = $2 RETURNING *
+              )
+              SELECT ` + commentColumns + ` FROM c JOIN users AS u ON c.author_id = u.id;`
+	row := tx.QueryRow(query, comment.ID, comment.TaskId, comment.Body, comment.UpdatedAt)
+
+	err := scanComment(row, comment)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (r *commentRepository) DeleteComment(taskId, commentId uuid.UUID) error {
+	query := `DELETE FROM task_comments WHERE id = $1 AND task_id = $2;`
+	_, err := r.db.Exec(query, commentId, taskId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
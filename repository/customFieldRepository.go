@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const customFieldColumns = `f.id, f.project_id, f.name, f.type, f.options, f.created_at, f.updated_at`
+
+func scanCustomField(row rowScanner, f *models.CustomField) error {
+	return row.Scan(&f.ID, &f.ProjectId, &f.Name, &f.Type, pq.Array(&f.Options), &f.CreatedAt, &f.UpdatedAt)
+}
+
+type CustomFieldRepository interface {
+	CreateCustomField(field *models.CustomField) (*models.CustomField, error)
+	GetCustomFields(projectId uuid.UUID) ([]*models.CustomField, error)
+	UpdateCustomField(field *models.CustomField) (*models.CustomField, error)
+	DeleteCustomField(projectId, fieldId uuid.UUID) error
+}
+
+type customFieldRepository struct {
+	db *sql.DB
+}
+
+func NewCustomFieldRepository(db *sql.DB) CustomFieldRepository {
+	return &customFieldRepository{db: db}
+}
+
+func (r *customFieldRepository) CreateCustomField(field *models.CustomField) (*models.CustomField, error) {
+	query := `INSERT INTO custom_fields AS f (id, project_id, name, type, options) VALUES ($1, $2, $3, $4, $5) RETURNING ` + customFieldColumns + `;`
+	row := r.db.QueryRow(query, field.ID, field.ProjectId, field.Name, field.Type, pq.Array(field.Options))
+
+	err := scanCustomField(row, field)
+	if err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (r *customFieldRepository) GetCustomFields(projectId uuid.UUID) ([]*models.CustomField, error) {
+	query := `SELECT ` + customFieldColumns + ` FROM custom_fields AS f WHERE f.project_id = $1 ORDER BY f.created_at, f.id;`
+	rows, err := r.db.Query(query, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := make([]*models.CustomField, 0)
+	for rows.Next() {
+		var f models.CustomField
+		err = scanCustomField(rows, &f)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &f)
+	}
+	return fields, rows.Err()
+}
+
+func (r *customFieldRepository) UpdateCustomField(field *models.CustomField) (*models.CustomField, error) {
+	query := `UPDATE custom_fields AS f SET name = $3, options = $4, updated_at = $5 WHERE f.id = $1 AND f.project_id = $2 RETURNING ` + customFieldColumns + `;`
+	row := r.db.QueryRow(query, field.ID, field.ProjectId, field.Name, pq.Array(field.Options), field.UpdatedAt)
+
+	err := scanCustomField(row, field)
+	if err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (r *customFieldRepository) DeleteCustomField(projectId, fieldId uuid.UUID) error {
+	query := `DELETE FROM custom_fields WHERE id = $1 AND project_id = $2;`
+	result, err := r.db.Exec(query, fieldId, projectId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
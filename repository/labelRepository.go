@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+const labelColumns = `l.id, l.project_id, l.name, l.color, l.created_at, l.updated_at`
+
+func scanLabel(row rowScanner, l *models.Label) error {
+	return row.Scan(&l.ID, &l.ProjectId, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt)
+}
+
+type LabelRepository interface {
+	CreateLabel(label *models.Label) (*models.Label, error)
+	GetLabels(projectId uuid.UUID) ([]*models.Label, error)
+	GetLabelById(projectId, labelId uuid.UUID) (*models.Label, error)
+	UpdateLabel(label *models.Label) (*models.Label, error)
+	DeleteLabel(projectId, labelId uuid.UUID) error
+	AttachLabelTx(tx *sql.Tx, taskId, labelId uuid.UUID) error
+	DetachLabelTx(tx *sql.Tx, taskId, labelId uuid.UUID) error
+}
+
+type labelRepository struct {
+	db *sql.DB
+}
+
+func NewLabelRepository(db *sql.DB) LabelRepository {
+	return &labelRepository{db: db}
+}
+
+func (r *labelRepository) CreateLabel(label *models.Label) (*models.Label, error) {
+	query := `INSERT INTO labels AS l (id, project_id, name, color) VALUES ($1, $2, $3, $4) RETURNING ` + labelColumns + `;`
+	row := r.db.QueryRow(query, label.ID, label.ProjectId, label.Name, label.Color)
+
+	err := scanLabel(row, label)
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// GetLabels returns the labels of a project along with the number of tasks carrying each of them.
+func (r *labelRepository) GetLabels(projectId uuid.UUID) ([]*models.Label, error) {
+	query := `SELECT ` + labelColumns + `, COUNT(tl.task_id) FROM labels AS l LEFT JOIN task_labels AS tl ON tl.label_id = l.id WHERE l.project_id = $1 GROUP BY l.id ORDER BY LOWER(l.name);`
+	rows, err := r.db.Query(query, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := make([]*models.Label, 0)
+	for rows.Next() {
+		var l models.Label
+		err = rows.Scan(&l.ID, &l.ProjectId, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt, &l.TaskCount)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, &l)
+	}
+	return labels, rows.Err()
+}
+
+func (r *labelRepository) GetLabelById(projectId, labelId uuid.UUID) (*models.Label, error) {
+	var l models.Label
+	query := `SELECT ` + labelColumns + `, (SELECT COUNT(*) FROM task_labels AS tl WHERE tl.label_id = l.id) FROM labels AS l WHERE l.id = $1 AND l.project_id = $2;`
+
+	row := r.db.QueryRow(query, labelId, projectId)
+	err := row.Scan(&l.ID, &l.ProjectId, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt, &l.TaskCount)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *labelRepository) UpdateLabel(label *models.Label) (*models.Label, error) {
+	query := `UPDATE labels AS l SET name = $3, color = $4, updated_at = $5 WHERE l.id = $1 AND l.project_id = $2 RETURNING ` + labelColumns + `, (SELECT COUNT(*) FROM task_labels AS tl WHERE tl.label_id = l.id);`
+	row := r.db.QueryRow(query, label.ID, label.ProjectId, label.Name, label.Color, label.UpdatedAt)
+
+	err := row.Scan(&label.ID, &label.ProjectId, &label.Name, &label.Color, &label.CreatedAt, &label.UpdatedAt, &label.TaskCount)
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+func (r *labelRepository) DeleteLabel(projectId, labelId uuid.UUID) error {
+	query := `DELETE FROM labels WHERE id = $1 AND project_id = $2;`
+	result, err := r.db.Exec(query, labelId, projectId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *labelRepository) AttachLabelTx(tx *sql.Tx, taskId, labelId uuid.UUID) error {
+	query := `INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2);`
+	_, err := tx.Exec(query, taskId, labelId)
+	return err
+}
+
+func (r *labelRepository) DetachLabelTx(tx *sql.Tx, taskId, labelId uuid.UUID) error {
+	query := `DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2;`
+	result, err := tx.Exec(query, taskId, labelId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
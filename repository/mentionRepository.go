@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+type MentionRepository interface {
+	CreateMentionTx(tx *sql.Tx, mention *models.Mention) (*models.Mention, error)
+	GetMentions(taskId uuid.UUID, commentId *uuid.UUID) ([]*models.Mention, error)
+	DeleteMentionsTx(tx *sql.Tx, taskId uuid.UUID, commentId *uuid.UUID) error
+}
+
+type mentionRepository struct {
+	db *sql.DB
+}
+
+func NewMentionRepository(db *sql.DB) MentionRepository {
+	return &mentionRepository{db: db}
+}
+
+func (r *mentionRepository) CreateMentionTx(tx *sql.Tx, mention *models.Mention) (*models.Mention, error) {
+	query := `INSERT INTO mentions (id, project_id, task_id, comment_id, user_id, mentioned_by, handle) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at;`
+	row := tx.QueryRow(query, mention.ID, mention.ProjectId, mention.TaskId, mention.CommentId, mention.UserId, mention.MentionedBy, mention.Handle)
+
+	err := row.Scan(&mention.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return mention, nil
+}
+
+// GetMentions returns the mentions made in a task description when commentId is nil, or in the given comment.
+func (r *mentionRepository) GetMentions(taskId uuid.UUID, commentId *uuid.UUID) ([]*models.Mention, error) {
+	query := `SELECT id, project_id, task_id, comment_id, user_id, mentioned_by, handle, created_at FROM mentions WHERE task_id = $1 AND comment_id IS NOT DISTINCT FROM $2 ORDER BY created_at;`
+	rows, err := r.db.Query(query, taskId, commentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mentions []*models.Mention
+	for rows.Next() {
+		var m models.Mention
+		err = rows.Scan(&m.ID, &m.ProjectId, &m.TaskId, &m.CommentId, &m.UserId, &m.MentionedBy, &m.Handle, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, &m)
+	}
+	return mentions, rows.Err()
+}
+
+func (r *mentionRepository) DeleteMentionsTx(tx *sql.Tx, taskId uuid.UUID, commentId *uuid.UUID) error {
+	query := `DELETE FROM mentions WHERE task_id = $1 AND comment_id IS NOT DISTINCT FROM $2;`
+	_, err := tx.Exec(query, taskId, commentId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const milestoneColumns = `m.id, m.project_id, m.name, m.description, m.due_date, m.created_at, m.updated_at`
+
+func scanMilestone(row rowScanner, m *models.Milestone) error {
+	return row.Scan(&m.ID, &m.ProjectId, &m.Name, &m.Description, &m.DueDate, &m.CreatedAt, &m.UpdatedAt)
+}
+
+type MilestoneRepository interface {
+	CreateMilestone(milestone *models.Milestone) (*models.Milestone, error)
+	GetMilestones(projectId uuid.UUID) ([]*models.Milestone, error)
+	GetMilestoneById(projectId, milestoneId uuid.UUID) (*models.Milestone, error)
+	UpdateMilestone(milestone *models.Milestone) (*models.Milestone, error)
+	DeleteMilestone(projectId, milestoneId uuid.UUID) error
+	GetMilestoneProgress(milestoneIds []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error)
+}
+
+type milestoneRepository struct {
+	db *sql.DB
+}
+
+func NewMilestoneRepository(db *sql.DB) MilestoneRepository {
+	return &milestoneRepository{db: db}
+}
+
+func (r *milestoneRepository) CreateMilestone(milestone *models.Milestone) (*models.Milestone, error) {
+	query := `INSERT INTO milestones AS m (id, project_id, name, description, due_date) VALUES ($1, $2, $3, $4, $5) RETURNING ` + milestoneColumns + `;`
+	row := r.db.QueryRow(query, milestone.ID, milestone.ProjectId, milestone.Name, milestone.Description, milestone.DueDate)
+
+	err := scanMilestone(row, milestone)
+	if err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (r *milestoneRepository) GetMilestones(projectId uuid.UUID) ([]*models.Milestone, error) {
+	query := `SELECT ` + milestoneColumns + ` FROM milestones AS m WHERE m.project_id = $1 ORDER BY m.due_date, LOWER(m.name);`
+	rows, err := r.db.Query(query, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := make([]*models.Milestone, 0)
+	for rows.Next() {
+		var m models.Milestone
+		err = scanMilestone(rows, &m)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, &m)
+	}
+	return milestones, rows.Err()
+}
+
+func (r *milestoneRepository) GetMilestoneById(projectId, milestoneId uuid.UUID) (*models.Milestone, error) {
+	var m models.Milestone
+	query := `SELECT ` + milestoneColumns + ` FROM milestones AS m WHERE m.id = $1 AND m.project_id = $2;`
+
+	row := r.db.QueryRow(query, milestoneId, projectId)
+	err := scanMilestone(row, &m)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *milestoneRepository) UpdateMilestone(milestone *models.Milestone) (*models.Milestone, error) {
+	query := `UPDATE milestones AS m SET name = $3, description = $4, due_date = $5, updated_at = $6 WHERE m.id = $1 AND m.project_id = $2 RETURNING ` + milestoneColumns + `;`
+	row := r.db.QueryRow(query, milestone.ID, milestone.ProjectId, milestone.Name, milestone.Description, milestone.DueDate, milestone.UpdatedAt)
+
+	err := scanMilestone(row, milestone)
+	if err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// DeleteMilestone removes the milestone, its tasks stay in the project without one.
+func (r *milestoneRepository) DeleteMilestone(projectId, milestoneId uuid.UUID) error {
+	query := `DELETE FROM milestones WHERE id = $1 AND project_id = $2;`
+	result, err := r.db.Exec(query, milestoneId, projectId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetMilestoneProgress counts the total and done tasks of each milestone. Milestones without tasks are left out.
+func (r *milestoneRepository) GetMilestoneProgress(milestoneIds []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error) {
+	progress := make(map[uuid.UUID]*models.TaskProgress)
+	if len(milestoneIds) == 0 {
+		return progress, nil
+	}
+
+	query := `SELECT milestone_id, COUNT(*), COUNT(*) FILTER (WHERE LOWER(status) = ANY($2)),
+                     COALESCE(SUM(estimate), 0), COALESCE(SUM(estimate) FILTER (WHERE LOWER(status) = ANY($2)), 0)
+              FROM tasks WHERE milestone_id = ANY($1) GROUP BY milestone_id;`
+	rows, err := r.db.Query(query, pq.Array(milestoneIds), pq.Array(models.GetDoneStatuses()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var milestoneId uuid.UUID
+		var total, done int
+		var estimate, doneEstimate float64
+		err = rows.Scan(&milestoneId, &total, &done, &estimate, &doneEstimate)
+		if err != nil {
+			return nil, err
+		}
+		progress[milestoneId] = models.NewTaskProgress(total, done, estimate, doneEstimate)
+	}
+	return progress, rows.Err()
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+const notificationColumns = `n.id, n.user_id, n.type, n.project_id, n.task_id, n.comment_id, n.actor_id, n.message, n.read_at, n.created_at`
+
+const createNotificationQuery = `INSERT INTO notifications (id, user_id, type, project_id, task_id, comment_id, actor_id, message) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING read_at, created_at;`
+
+func scanNotification(row rowScanner, n *models.Notification) error {
+	return row.Scan(&n.ID, &n.UserId, &n.Type, &n.ProjectId, &n.TaskId, &n.CommentId, &n.ActorId, &n.Message, &n.ReadAt, &n.CreatedAt)
+}
+
+type NotificationRepository interface {
+	CreateNotification(notification *models.Notification) (*models.Notification, error)
+	CreateNotificationTx(tx *sql.Tx, notification *models.Notification) (*models.Notification, error)
+	GetNotifications(userId uuid.UUID, unreadOnly bool, page uint, perPage uint) ([]*models.Notification, error)
+	GetUnreadCount(userId uuid.UUID) (int, error)
+	MarkRead(userId, notificationId uuid.UUID, readAt time.Time) (*models.Notification, error)
+	MarkAllRead(userId uuid.UUID, notificationIds []uuid.UUID, readAt time.Time) (int, error)
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateNotification(notification *models.Notification) (*models.Notification, error) {
+	row := r.db.QueryRow(createNotificationQuery, notification.ID, notification.UserId, notification.Type.String(), notification.ProjectId, notification.TaskId, notification.CommentId, notification.ActorId, notification.Message)
+
+	err := row.Scan(&notification.ReadAt, &notification.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+func (r *notificationRepository) CreateNotificationTx(tx *sql.Tx, notification *models.Notification) (*models.Notification, error) {
+	row := tx.QueryRow(createNotificationQuery, notification.ID, notification.UserId, notification.Type.String(), notification.ProjectId, notification.TaskId, notification.CommentId, notification.ActorId, notification.Message)
+
+	err := row.Scan(&notification.ReadAt, &notification.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// GetNotifications returns a page of the notifications of the user, newest first.
+func (r *notificationRepository) GetNotifications(userId uuid.UUID, unreadOnly bool, page uint, perPage uint) ([]*models.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications AS n WHERE n.user_id = $1 AND (NOT $2 OR n.read_at IS NULL)
+              ORDER BY n.created_at DESC, n.id LIMIT $3 OFFSET $4;`
+	rows, err := r.db.Query(query, userId, unreadOnly, perPage, page*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]*models.Notification, 0)
+	for rows.Next() {
+		var n models.Notification
+		err = scanNotification(rows, &n)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *notificationRepository) GetUnreadCount(userId uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL;`
+	err := r.db.QueryRow(query, userId).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks a notification of the user read, one that was read before keeps its read time.
+func (r *notificationRepository) MarkRead(userId, notificationId uuid.UUID, readAt time.Time) (*models.Notification, error) {
+	query := `UPDATE notifications AS n SET read_at = COALESCE(n.read_at, $3) WHERE n.id = $1 AND n.user_id = $2 RETURNING ` + notificationColumns + `;`
+	row := r.db.QueryRow(query, notificationId, userId, readAt)
+
+	var n models.Notification
+	err := scanNotification(row, &n)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// MarkAllRead marks the unread notifications of the user with the given ids read, or all of them without ids,
+// and returns how many it marked.
+func (r *notificationRepository) MarkAllRead(userId uuid.UUID, notificationIds []uuid.UUID, readAt time.Time) (int, error) {
+	query := `UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL AND (cardinality($3::uuid[]) = 0 OR id = ANY($3));`
+	if notificationIds == nil {
+		notificationIds = []uuid.UUID{}
+	}
+	result, err := r.db.Exec(query, userId, readAt, pq.Array(notificationIds))
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
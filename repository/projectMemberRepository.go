@@ -12,6 +12,7 @@ type ProjectMemberRepository interface {
 	GetMember(projectId, userId uuid.UUID) (*models.ProjectMember, error)
 	GetMembers(projectId uuid.UUID) ([]*models.ProjectMember, error)
 	DeleteMember(projectId, userId uuid.UUID) error
+	DeleteMemberTx(tx *sql.Tx, projectId, userId uuid.UUID) error
 }
 
 type projectMemberRepository struct {
@@ -84,3 +85,12 @@ func (r *projectMemberRepository) DeleteMember(projectId, userId uuid.UUID) erro
 	}
 	return nil
 }
+
+func (r *projectMemberRepository) DeleteMemberTx(tx *sql.Tx, projectId, userId uuid.UUID) error {
+	query := `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2;`
+	_, err := tx.Exec(query, projectId, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -4,14 +4,24 @@ import (
 	"database/sql"
 	"github.com/drTragger/MykroTask/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const projectColumns = `p.id, p.key, p.name, p.description, p.estimate_unit, p.start_date, p.end_date, p.owner_id, p.created_at, p.updated_at, p.version`
+
+func scanProject(row rowScanner, p *models.Project) error {
+	return row.Scan(&p.ID, &p.Key, &p.Name, &p.Description, &p.EstimateUnit, &p.StartDate, &p.EndDate, &p.OwnerId, &p.CreatedAt, &p.UpdatedAt, &p.Version)
+}
+
 type ProjectRepository interface {
 	CreateProjectTx(tx *sql.Tx, project *models.Project) (*models.Project, error)
 	GetProjectsForUser(userId uuid.UUID, page uint, perPage uint) ([]*models.Project, error)
 	GetProjectById(projectId, memberId uuid.UUID) (*models.Project, error)
-	UpdateProject(project *models.Project) (*models.Project, error)
+	UpdateProjectTx(tx *sql.Tx, project *models.Project) (*models.Project, error)
+	ClaimKeyTx(tx *sql.Tx, projectId uuid.UUID, key string) error
+	IsKeyTaken(key string) (bool, error)
 	DeleteProject(projectId uuid.UUID) error
+	GetProjectProgress(projectId uuid.UUID) (*models.TaskProgress, error)
 }
 
 type projectRepository struct {
@@ -23,12 +33,12 @@ func NewProjectRepository(db *sql.DB) ProjectRepository {
 }
 
 func (r *projectRepository) CreateProjectTx(tx *sql.Tx, project *models.Project) (*models.Project, error) {
-	query := `INSERT INTO projects (id, name, description, start_date, end_date, owner_id) 
-              VALUES ($1, $2, $3, $4, $5, $6) RETURNING *`
-	row := tx.QueryRow(query, project.ID, project.Name, project.Description, project.StartDate, project.EndDate, project.OwnerId)
+	query := `INSERT INTO projects AS p (id, key, name, description, estimate_unit, start_date, end_date, owner_id) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + projectColumns
+	row := tx.QueryRow(query, project.ID, project.Key, project.Name, project.Description, project.EstimateUnit, project.StartDate, project.EndDate, project.OwnerId)
 
 	var p models.Project
-	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.StartDate, &p.EndDate, &p.OwnerId, &p.CreatedAt, &p.UpdatedAt)
+	err := scanProject(row, &p)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +47,7 @@ func (r *projectRepository) CreateProjectTx(tx *sql.Tx, project *models.Project)
 
 func (r *projectRepository) GetProjectsForUser(userId uuid.UUID, page uint, perPage uint) ([]*models.Project, error) {
 	var projects []*models.Project
-	query := `SELECT p.id, p.name, p.description, p.start_date, p.end_date, p.owner_id, p.created_at, p.updated_at FROM projects AS p LEFT JOIN project_members AS pm ON p.id = pm.project_id WHERE pm.user_id = $1 LIMIT $2 OFFSET $3`
+	query := `SELECT ` + projectColumns + ` FROM projects AS p LEFT JOIN project_members AS pm ON p.id = pm.project_id WHERE pm.user_id = $1 LIMIT $2 OFFSET $3`
 	rows, err := r.db.Query(query, userId, perPage, page*perPage)
 	if err != nil {
 		return nil, err
@@ -46,7 +56,7 @@ func (r *projectRepository) GetProjectsForUser(userId uuid.UUID, page uint, perP
 
 	for rows.Next() {
 		var p models.Project
-		err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.StartDate, &p.EndDate, &p.OwnerId, &p.CreatedAt, &p.UpdatedAt)
+		err := scanProject(rows, &p)
 		if err != nil {
 			return nil, err
 		}
@@ -56,30 +66,49 @@ func (r *projectRepository) GetProjectsForUser(userId uuid.UUID, page uint, perP
 }
 
 func (r *projectRepository) GetProjectById(projectId, memberId uuid.UUID) (*models.Project, error) {
-	query := `SELECT p.id, p.name, p.description, p.start_date, p.end_date, p.owner_id, p.created_at, p.updated_at FROM projects AS p JOIN project_members AS pm ON p.id = pm.project_id WHERE p.id = $1 AND pm.user_id = $2`
+	query := `SELECT ` + projectColumns + ` FROM projects AS p JOIN project_members AS pm ON p.id = pm.project_id WHERE p.id = $1 AND pm.user_id = $2`
 	row := r.db.QueryRow(query, projectId, memberId)
 
 	var p models.Project
-	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.StartDate, &p.EndDate, &p.OwnerId, &p.CreatedAt, &p.UpdatedAt)
+	err := scanProject(row, &p)
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *projectRepository) UpdateProject(project *models.Project) (*models.Project, error) {
-	query := `UPDATE projects SET name = $2, description = $3, end_date = $4, updated_at = $5 
-              WHERE id = $1 RETURNING id, name, description, start_date, end_date, owner_id, created_at, updated_at`
-	row := r.db.QueryRow(query, project.ID, project.Name, project.Description, project.EndDate, project.UpdatedAt)
+// UpdateProjectTx saves the project, keeping the current key and estimate unit when none is given.
+func (r *projectRepository) UpdateProjectTx(tx *sql.Tx, project *models.Project) (*models.Project, error) {
+	query := `UPDATE projects AS p SET key = COALESCE(NULLIF($2, ''), p.key), name = $3, description = $4, estimate_unit = COALESCE(NULLIF($8, ''), p.estimate_unit),
+              end_date = $5, updated_at = $6, version = p.version + 1 
+              WHERE p.id = $1 AND p.version = $7 RETURNING ` + projectColumns
+	row := tx.QueryRow(query, project.ID, project.Key, project.Name, project.Description, project.EndDate, project.UpdatedAt, project.Version, project.EstimateUnit)
 
 	var p models.Project
-	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.StartDate, &p.EndDate, &p.OwnerId, &p.CreatedAt, &p.UpdatedAt)
+	err := scanProject(row, &p)
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
+// ClaimKeyTx records the key as used by the project. It returns sql.ErrNoRows when another project has ever used it.
+func (r *projectRepository) ClaimKeyTx(tx *sql.Tx, projectId uuid.UUID, key string) error {
+	query := `INSERT INTO project_keys AS k (key, project_id) VALUES ($1, $2)
+              ON CONFLICT (key) DO UPDATE SET project_id = k.project_id WHERE k.project_id = EXCLUDED.project_id RETURNING k.key`
+
+	var claimed string
+	return tx.QueryRow(query, key, projectId).Scan(&claimed)
+}
+
+func (r *projectRepository) IsKeyTaken(key string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM project_keys WHERE key = $1)`
+
+	var taken bool
+	err := r.db.QueryRow(query, key).Scan(&taken)
+	return taken, err
+}
+
 func (r *projectRepository) DeleteProject(projectId uuid.UUID) error {
 	query := `DELETE FROM projects WHERE id = $1`
 	_, err := r.db.Exec(query, projectId)
@@ -88,3 +117,18 @@ func (r *projectRepository) DeleteProject(projectId uuid.UUID) error {
 	}
 	return nil
 }
+
+// GetProjectProgress counts the total and done tasks of the project along with their estimates.
+func (r *projectRepository) GetProjectProgress(projectId uuid.UUID) (*models.TaskProgress, error) {
+	query := `SELECT COUNT(*), COUNT(*) FILTER (WHERE LOWER(status) = ANY($2)),
+                     COALESCE(SUM(estimate), 0), COALESCE(SUM(estimate) FILTER (WHERE LOWER(status) = ANY($2)), 0)
+              FROM tasks WHERE project_id = $1`
+
+	var total, done int
+	var estimate, doneEstimate float64
+	err := r.db.QueryRow(query, projectId, pq.Array(models.GetDoneStatuses())).Scan(&total, &done, &estimate, &doneEstimate)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewTaskProgress(total, done, estimate, doneEstimate), nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/lib/pq"
+	"time"
+)
+
+// remindersLockKey is the advisory lock that lets one server at a time send reminders.
+const remindersLockKey = "task_reminders"
+
+// taskDueAt is the moment a task becomes overdue, the end of its due date in UTC.
+const taskDueAt = `((t.due_date + 1)::timestamp AT TIME ZONE 'UTC')`
+
+type ReminderRepository interface {
+	TryLockTx(tx *sql.Tx) (bool, error)
+	ClaimRemindersTx(tx *sql.Tx, kind models.ReminderKind, offset time.Duration, from, to time.Time) ([]*models.DueReminder, error)
+}
+
+type reminderRepository struct {
+	db *sql.DB
+}
+
+func NewReminderRepository(db *sql.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// TryLockTx takes the reminders lock until the transaction ends, or reports false when another server holds it.
+func (r *reminderRepository) TryLockTx(tx *sql.Tx) (bool, error) {
+	var locked bool
+	err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock(hashtext($1));`, remindersLockKey).Scan(&locked)
+	return locked, err
+}
+
+// ClaimRemindersTx records a reminder of the kind and offset for every assignee of an unfinished task that
+// becomes overdue after from and no later than to, and returns the reminders that had not been sent yet.
+func (r *reminderRepository) ClaimRemindersTx(tx *sql.Tx, kind models.ReminderKind, offset time.Duration, from, to time.Time) ([]*models.DueReminder, error) {
+	query := `WITH claimed AS (
+                  INSERT INTO task_reminders (task_id, user_id, due_date, kind, offset_minutes)
+                  SELECT t.id, a.user_id, t.due_date, $1, $2 FROM tasks AS t JOIN task_assignees AS a ON a.task_id = t.id
+                  WHERE t.due_date IS NOT NULL AND NOT LOWER(t.status) = ANY($5) AND ` + taskDueAt + ` > $3 AND ` + taskDueAt + ` <= $4
+                  ON CONFLICT DO NOTHING RETURNING task_id, user_id
+              )
+              SELECT t.id, t.project_id, ` + taskKeyColumn + `, t.title, t.due_date, c.user_id FROM claimed AS c JOIN tasks AS t ON t.id = c.task_id
+              ORDER BY t.due_date, t.id, c.user_id;`
+	rows, err := tx.Query(query, string(kind), int(offset.Minutes()), from, to, pq.Array(models.GetDoneStatuses()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []*models.DueReminder
+	for rows.Next() {
+		reminder := models.DueReminder{Kind: kind}
+		err = rows.Scan(&reminder.TaskId, &reminder.ProjectId, &reminder.TaskKey, &reminder.Title, &reminder.DueDate, &reminder.UserId)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &reminder)
+	}
+	return reminders, rows.Err()
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const savedViewColumns = `v.id, v.project_id, v.owner_id, v.name, v.filter, v.columns, v.shared, v.created_at, v.updated_at`
+
+func scanSavedView(row rowScanner, v *models.SavedView) error {
+	return row.Scan(&v.ID, &v.ProjectId, &v.OwnerId, &v.Name, &v.Filter, pq.Array(&v.Columns), &v.Shared, &v.CreatedAt, &v.UpdatedAt)
+}
+
+type SavedViewRepository interface {
+	CreateView(view *models.SavedView) (*models.SavedView, error)
+	GetViews(projectId, userId uuid.UUID) ([]*models.SavedView, error)
+	GetViewById(projectId, viewId uuid.UUID) (*models.SavedView, error)
+	UpdateView(view *models.SavedView) (*models.SavedView, error)
+	DeleteView(projectId, viewId uuid.UUID) error
+}
+
+type savedViewRepository struct {
+	db *sql.DB
+}
+
+func NewSavedViewRepository(db *sql.DB) SavedViewRepository {
+	return &savedViewRepository{db: db}
+}
+
+func (r *savedViewRepository) CreateView(view *models.SavedView) (*models.SavedView, error) {
+	query := `INSERT INTO saved_views AS v (id, project_id, owner_id, name, filter, columns, shared) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + savedViewColumns + `;`
+	row := r.db.QueryRow(query, view.ID, view.ProjectId, view.OwnerId, view.Name, view.Filter, pq.Array(view.Columns), view.Shared)
+
+	err := scanSavedView(row, view)
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// GetViews returns the user's own views of a project followed by the views shared by others.
+func (r *savedViewRepository) GetViews(projectId, userId uuid.UUID) ([]*models.SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views AS v WHERE v.project_id = $1 AND (v.owner_id = $2 OR v.shared) ORDER BY v.owner_id <> $2, LOWER(v.name), v.id;`
+	rows, err := r.db.Query(query, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := make([]*models.SavedView, 0)
+	for rows.Next() {
+		var v models.SavedView
+		err = scanSavedView(rows, &v)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &v)
+	}
+	return views, rows.Err()
+}
+
+func (r *savedViewRepository) GetViewById(projectId, viewId uuid.UUID) (*models.SavedView, error) {
+	var v models.SavedView
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views AS v WHERE v.id = $1 AND v.project_id = $2;`
+
+	err := scanSavedView(r.db.QueryRow(query, viewId, projectId), &v)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *savedViewRepository) UpdateView(view *models.SavedView) (*models.SavedView, error) {
+	query := `UPDATE saved_views AS v SET name = $3, filter = $4, columns = $5, shared = $6, updated_at = $7 WHERE v.id = $1 AND v.project_id = $2 RETURNING ` + savedViewColumns + `;`
+	row := r.db.QueryRow(query, view.ID, view.ProjectId, view.Name, view.Filter, pq.Array(view.Columns), view.Shared, view.UpdatedAt)
+
+	err := scanSavedView(row, view)
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (r *savedViewRepository) DeleteView(projectId, viewId uuid.UUID) error {
+	query := `DELETE FROM saved_views WHERE id = $1 AND project_id = $2;`
+	_, err := r.db.Exec(query, viewId, projectId)
+	return err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+const headlineOptions = `StartSel=` + models.HighlightStart + `, StopSel=` + models.HighlightStop + `, MaxWords=35, MinWords=15, MaxFragments=2`
+
+type SearchRepository interface {
+	SearchTasks(userId uuid.UUID, query string, page uint, perPage uint) ([]*models.SearchResult, error)
+}
+
+type searchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// SearchTasks ranks the tasks of the user's projects whose title, description or comments match the query.
+// A task matched through its comments carries the snippet of its best matching comment.
+func (r *searchRepository) SearchTasks(userId uuid.UUID, query string, page uint, perPage uint) ([]*models.SearchResult, error) {
+	sqlQuery := `WITH q AS (SELECT websearch_to_tsquery('english', $2) AS query),
+                  member_tasks AS (
+                      SELECT t.* FROM tasks AS t JOIN project_members AS pm ON pm.project_id = t.project_id AND pm.user_id = $1
+                  ),
+                  comment_hits AS (
+                      SELECT DISTINCT ON (c.task_id) c.task_id, c.id, c.body, ts_rank(c.search_vector, q.query) AS rank
+                      FROM task_comments AS c
+                               JOIN member_tasks AS t ON t.id = c.task_id
+                               CROSS JOIN q
+                      WHERE c.search_vector @@ q.query
+                      ORDER BY c.task_id, rank DESC, c.created_at
+                  )
+              SELECT t.id, t.project_id, t.title, t.status,
+                     ts_rank(t.search_vector, q.query) + COALESCE(ch.rank, 0) AS rank,
+                     ts_headline('english', t.title, q.query, 'HighlightAll=true, StartSel=` + models.HighlightStart + `, StopSel=` + models.HighlightStop + `'),
+                     ts_headline('english', COALESCE(t.description, ''), q.query, $3),
+                     ch.id, ts_headline('english', ch.body, q.query, $3)
+              FROM member_tasks AS t
+                       CROSS JOIN q
+                       LEFT JOIN comment_hits AS ch ON ch.task_id = t.id
+              WHERE t.search_vector @@ q.query OR ch.task_id IS NOT NULL
+              ORDER BY rank DESC, t.updated_at DESC, t.id
+              LIMIT $4 OFFSET $5;`
+
+	rows, err := r.db.Query(sqlQuery, userId, query, headlineOptions, perPage, page*perPage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*models.SearchResult, 0)
+	for rows.Next() {
+		var sr models.SearchResult
+		err = rows.Scan(&sr.TaskId, &sr.ProjectId, &sr.Title, &sr.Status, &sr.Rank, &sr.TitleSnippet, &sr.DescriptionSnippet, &sr.CommentId, &sr.CommentSnippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &sr)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const sprintColumns = `s.id, s.project_id, s.name, s.goal, s.start_date, s.end_date, s.state, s.committed_tasks, s.completed_tasks, s.carried_over_tasks, s.committed_estimate, s.completed_estimate, s.carried_over_estimate, s.started_at, s.closed_at, s.created_at, s.updated_at`
+
+func scanSprint(row rowScanner, s *models.Sprint) error {
+	return row.Scan(&s.ID, &s.ProjectId, &s.Name, &s.Goal, &s.StartDate, &s.EndDate, &s.State, &s.CommittedTasks, &s.CompletedTasks, &s.CarriedOverTasks, &s.CommittedEstimate, &s.CompletedEstimate, &s.CarriedOverEstimate, &s.StartedAt, &s.ClosedAt, &s.CreatedAt, &s.UpdatedAt)
+}
+
+type SprintRepository interface {
+	CreateSprint(sprint *models.Sprint) (*models.Sprint, error)
+	GetSprints(projectId uuid.UUID) ([]*models.Sprint, error)
+	GetSprintById(projectId, sprintId uuid.UUID) (*models.Sprint, error)
+	GetSprintByIdForUpdateTx(tx *sql.Tx, projectId, sprintId uuid.UUID) (*models.Sprint, error)
+	UpdateSprint(sprint *models.Sprint) (*models.Sprint, error)
+	UpdateSprintStateTx(tx *sql.Tx, sprint *models.Sprint) (*models.Sprint, error)
+	DeleteSprint(projectId, sprintId uuid.UUID) error
+	GetSprintProgress(sprintIds []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error)
+}
+
+type sprintRepository struct {
+	db *sql.DB
+}
+
+func NewSprintRepository(db *sql.DB) SprintRepository {
+	return &sprintRepository{db: db}
+}
+
+func (r *sprintRepository) CreateSprint(sprint *models.Sprint) (*models.Sprint, error) {
+	query := `INSERT INTO sprints AS s (id, project_id, name, goal, start_date, end_date, state) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + sprintColumns + `;`
+	row := r.db.QueryRow(query, sprint.ID, sprint.ProjectId, sprint.Name, sprint.Goal, sprint.StartDate, sprint.EndDate, sprint.State)
+
+	err := scanSprint(row, sprint)
+	if err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+func (r *sprintRepository) GetSprints(projectId uuid.UUID) ([]*models.Sprint, error) {
+	query := `SELECT ` + sprintColumns + ` FROM sprints AS s WHERE s.project_id = $1 ORDER BY s.start_date, s.created_at;`
+	rows, err := r.db.Query(query, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sprints := make([]*models.Sprint, 0)
+	for rows.Next() {
+		var s models.Sprint
+		err = scanSprint(rows, &s)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, &s)
+	}
+	return sprints, rows.Err()
+}
+
+func (r *sprintRepository) GetSprintById(projectId, sprintId uuid.UUID) (*models.Sprint, error) {
+	var s models.Sprint
+	query := `SELECT ` + sprintColumns + ` FROM sprints AS s WHERE s.id = $1 AND s.project_id = $2;`
+
+	row := r.db.QueryRow(query, sprintId, projectId)
+	err := scanSprint(row, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetSprintByIdForUpdateTx loads a sprint and locks its row until the transaction ends.
+func (r *sprintRepository) GetSprintByIdForUpdateTx(tx *sql.Tx, projectId, sprintId uuid.UUID) (*models.Sprint, error) {
+	var s models.Sprint
+	query := `SELECT ` + sprintColumns + ` FROM sprints AS s WHERE s.id = $1 AND s.project_id = $2 FOR UPDATE;`
+
+	row := tx.QueryRow(query, sprintId, projectId)
+	err := scanSprint(row, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpdateSprint saves the plan of a sprint that is not closed yet.
+func (r *sprintRepository) UpdateSprint(sprint *models.Sprint) (*models.Sprint, error) {
+	query := `UPDATE sprints AS s SET name = $3, goal = $4, start_date = $5, end_date = $6, updated_at = $7 WHERE s.id = $1 AND s.project_id = $2 AND s.state <> $8 RETURNING ` + sprintColumns + `;`
+	row := r.db.QueryRow(query, sprint.ID, sprint.ProjectId, sprint.Name, sprint.Goal, sprint.StartDate, sprint.EndDate, sprint.UpdatedAt, models.SprintClosed)
+
+	err := scanSprint(row, sprint)
+	if err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// UpdateSprintStateTx saves the state of a sprint along with the counts and estimates recorded on the way.
+func (r *sprintRepository) UpdateSprintStateTx(tx *sql.Tx, sprint *models.Sprint) (*models.Sprint, error) {
+	query := `UPDATE sprints AS s SET state = $3, committed_tasks = $4, completed_tasks = $5, carried_over_tasks = $6,
+                  committed_estimate = $7, completed_estimate = $8, carried_over_estimate = $9, started_at = $10, closed_at = $11, updated_at = $12
+              WHERE s.id = $1 AND s.project_id = $2 RETURNING ` + sprintColumns + `;`
+	row := tx.QueryRow(query, sprint.ID, sprint.ProjectId, sprint.State, sprint.CommittedTasks, sprint.CompletedTasks, sprint.CarriedOverTasks,
+		sprint.CommittedEstimate, sprint.CompletedEstimate, sprint.CarriedOverEstimate, sprint.StartedAt, sprint.ClosedAt, sprint.UpdatedAt)
+
+	err := scanSprint(row, sprint)
+	if err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// DeleteSprint removes the sprint, its tasks go back to the backlog.
+func (r *sprintRepository) DeleteSprint(projectId, sprintId uuid.UUID) error {
+	query := `DELETE FROM sprints WHERE id = $1 AND project_id = $2;`
+	result, err := r.db.Exec(query, sprintId, projectId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetSprintProgress counts the total and done tasks of each sprint. Sprints without tasks are left out.
+func (r *sprintRepository) GetSprintProgress(sprintIds []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error) {
+	progress := make(map[uuid.UUID]*models.TaskProgress)
+	if len(sprintIds) == 0 {
+		return progress, nil
+	}
+
+	query := `SELECT sprint_id, COUNT(*), COUNT(*) FILTER (WHERE LOWER(status) = ANY($2)),
+                     COALESCE(SUM(estimate), 0), COALESCE(SUM(estimate) FILTER (WHERE LOWER(status) = ANY($2)), 0)
+              FROM tasks WHERE sprint_id = ANY($1) GROUP BY sprint_id;`
+	rows, err := r.db.Query(query, pq.Array(sprintIds), pq.Array(models.GetDoneStatuses()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sprintId uuid.UUID
+		var total, done int
+		var estimate, doneEstimate float64
+		err = rows.Scan(&sprintId, &total, &done, &estimate, &doneEstimate)
+		if err != nil {
+			return nil, err
+		}
+		progress[sprintId] = models.NewTaskProgress(total, done, estimate, doneEstimate)
+	}
+	return progress, rows.Err()
+}
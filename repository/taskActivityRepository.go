@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+const taskActivityColumns = `id, task_id, project_id, actor_id, action, changes, created_at`
+
+func scanTaskActivity(row rowScanner, a *models.TaskActivity) error {
+	var changes []byte
+	err := row.Scan(&a.ID, &a.TaskId, &a.ProjectId, &a.ActorId, &a.Action, &changes, &a.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(changes, &a.Changes)
+}
+
+type TaskActivityRepository interface {
+	CreateActivityTx(tx *sql.Tx, activity *models.TaskActivity) (*models.TaskActivity, error)
+	GetTaskActivities(projectId, taskId uuid.UUID) ([]*models.TaskActivity, error)
+	GetProjectActivities(projectId uuid.UUID, page uint, perPage uint) ([]*models.TaskActivity, error)
+}
+
+type taskActivityRepository struct {
+	db *sql.DB
+}
+
+func NewTaskActivityRepository(db *sql.DB) TaskActivityRepository {
+	return &taskActivityRepository{db: db}
+}
+
+func (r *taskActivityRepository) CreateActivityTx(tx *sql.Tx, activity *models.TaskActivity) (*models.TaskActivity, error) {
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO task_activities (id, task_id, project_id, actor_id, action, changes) VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at;`
+	err = tx.QueryRow(query, activity.ID, activity.TaskId, activity.ProjectId, activity.ActorId, activity.Action.String(), changes).Scan(&activity.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+func (r *taskActivityRepository) GetTaskActivities(projectId, taskId uuid.UUID) ([]*models.TaskActivity, error) {
+	query := `SELECT ` + taskActivityColumns + ` FROM task_activities WHERE project_id = $1 AND task_id = $2 ORDER BY created_at, id;`
+	return r.getActivities(query, projectId, taskId)
+}
+
+func (r *taskActivityRepository) GetProjectActivities(projectId uuid.UUID, page uint, perPage uint) ([]*models.TaskActivity, error) {
+	query := `SELECT ` + taskActivityColumns + ` FROM task_activities WHERE project_id = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3;`
+	return r.getActivities(query, projectId, perPage, page*perPage)
+}
+
+func (r *taskActivityRepository) getActivities(query string, args ...interface{}) ([]*models.TaskActivity, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []*models.TaskActivity
+	for rows.Next() {
+		var a models.TaskActivity
+		err = scanTaskActivity(rows, &a)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, &a)
+	}
+	return activities, rows.Err()
+}
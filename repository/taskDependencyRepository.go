@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+)
+
+type TaskDependencyRepository interface {
+	LockDependenciesTx(tx *sql.Tx, projectId uuid.UUID) error
+	CreateDependencyTx(tx *sql.Tx, dependency *models.TaskDependency) (*models.TaskDependency, error)
+	DeleteDependency(projectId, taskId, otherTaskId uuid.UUID) error
+	IsBlockingTx(tx *sql.Tx, blockerId, blockedId uuid.UUID) (bool, error)
+	GetBlockers(taskId uuid.UUID) ([]*models.TaskLink, error)
+	GetBlocking(taskId uuid.UUID) ([]*models.TaskLink, error)
+}
+
+type taskDependencyRepository struct {
+	db *sql.DB
+}
+
+func NewTaskDependencyRepository(db *sql.DB) TaskDependencyRepository {
+	return &taskDependencyRepository{db: db}
+}
+
+// LockDependenciesTx serializes dependency changes within a project until the transaction ends,
+// so two concurrent links cannot close a cycle that neither of them sees on its own.
+func (r *taskDependencyRepository) LockDependenciesTx(tx *sql.Tx, projectId uuid.UUID) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext('task_dependencies:' || $1::text));`
+	_, err := tx.Exec(query, projectId)
+	return err
+}
+
+func (r *taskDependencyRepository) CreateDependencyTx(tx *sql.Tx, dependency *models.TaskDependency) (*models.TaskDependency, error) {
+	query := `INSERT INTO task_dependencies (blocker_id, blocked_id, project_id, created_by) VALUES ($1, $2, $3, $4) RETURNING blocker_id, blocked_id, project_id, created_by, created_at;`
+	row := tx.QueryRow(query, dependency.BlockerId, dependency.BlockedId, dependency.ProjectId, dependency.CreatedBy)
+
+	err := row.Scan(&dependency.BlockerId, &dependency.BlockedId, &dependency.ProjectId, &dependency.CreatedBy, &dependency.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return dependency, nil
+}
+
+// DeleteDependency removes the link between two tasks regardless of its direction.
+func (r *taskDependencyRepository) DeleteDependency(projectId, taskId, otherTaskId uuid.UUID) error {
+	query := `DELETE FROM task_dependencies WHERE project_id = $1 AND ((blocker_id = $2 AND blocked_id = $3) OR (blocker_id = $3 AND blocked_id = $2));`
+	result, err := r.db.Exec(query, projectId, taskId, otherTaskId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// IsBlockingTx reports whether blockerId blocks blockedId directly or through a chain of dependencies.
+func (r *taskDependencyRepository) IsBlockingTx(tx *sql.Tx, blockerId, blockedId uuid.UUID) (bool, error) {
+	query := `WITH RECURSIVE chain AS (
+                  SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1
+                  UNION
+                  SELECT d.blocked_id FROM task_dependencies AS d JOIN chain AS c ON d.blocker_id = c.blocked_id
+              )
+              SELECT EXISTS (SELECT 1 FROM chain WHERE blocked_id = $2);`
+
+	var exists bool
+	err := tx.QueryRow(query, blockerId, blockedId).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *taskDependencyRepository) GetBlockers(taskId uuid.UUID) ([]*models.TaskLink, error) {
+	query := `SELECT t.id, t.title, t.status FROM task_dependencies AS d JOIN tasks AS t ON d.blocker_id = t.id WHERE d.blocked_id = $1 ORDER BY d.created_at;`
+	return r.getLinks(query, taskId)
+}
+
+func (r *taskDependencyRepository) GetBlocking(taskId uuid.UUID) ([]*models.TaskLink, error) {
+	query := `SELECT t.id, t.title, t.status FROM task_dependencies AS d JOIN tasks AS t ON d.blocked_id = t.id WHERE d.blocker_id = $1 ORDER BY d.created_at;`
+	return r.getLinks(query, taskId)
+}
+
+func (r *taskDependencyRepository) getLinks(query string, args ...interface{}) ([]*models.TaskLink, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*models.TaskLink
+	for rows.Next() {
+		var l models.TaskLink
+		err = rows.Scan(&l.ID, &l.Title, &l.Status)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, &l)
+	}
+	return links, rows.Err()
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+)
+
+// taskQuery builds a task SELECT from conditions whose values are always passed as placeholders.
+type taskQuery struct {
+	conditions []string
+	orderBy    []string
+	args       []interface{}
+}
+
+func newTaskQuery() *taskQuery {
+	return &taskQuery{}
+}
+
+// arg adds a value to the query and returns its placeholder.
+func (q *taskQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+func (q *taskQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *taskQuery) order(expression string) {
+	q.orderBy = append(q.orderBy, expression)
+}
+
+func (q *taskQuery) applyFilter(filter *models.TaskFilter) {
+	if filter == nil {
+		return
+	}
+
+	if len(filter.Statuses) > 0 {
+		q.where(`LOWER(t.status) = ANY(` + q.arg(pq.Array(lowerAll(filter.Statuses))) + `)`)
+	}
+	if len(filter.Priorities) > 0 {
+		q.where(`LOWER(t.priority) = ANY(` + q.arg(pq.Array(lowerAll(filter.Priorities))) + `)`)
+	}
+
+	var assignee []string
+	if len(filter.AssigneeIds) > 0 {
+		assignee = append(assignee, `EXISTS (SELECT 1 FROM task_assignees AS a WHERE a.task_id = t.id AND a.user_id = ANY(`+q.arg(pq.Array(filter.AssigneeIds))+`))`)
+	}
+	if filter.Unassigned {
+		assignee = append(assignee, `NOT EXISTS (SELECT 1 FROM task_assignees AS a WHERE a.task_id = t.id)`)
+	}
+	if len(assignee) > 0 {
+		q.where(`(` + strings.Join(assignee, ` OR `) + `)`)
+	}
+
+	if len(filter.CreatorIds) > 0 {
+		q.where(`t.created_by = ANY(` + q.arg(pq.Array(filter.CreatorIds)) + `)`)
+	}
+
+	q.whereDateRange(`t.due_date`, filter.Due)
+	q.whereDateRange(`t.created_at`, filter.Created)
+	q.whereDateRange(`t.updated_at`, filter.Updated)
+	if filter.Overdue {
+		q.where(`t.due_date < CURRENT_DATE AND NOT LOWER(t.status) = ANY(` + q.arg(pq.Array(models.GetDoneStatuses())) + `)`)
+	}
+
+	if filter.Search != "" {
+		pattern := q.arg(`%` + escapeLike(filter.Search) + `%`)
+		q.where(`(t.title ILIKE ` + pattern + ` OR t.description ILIKE ` + pattern + `)`)
+	}
+
+	var milestone []string
+	if len(filter.MilestoneIds) > 0 {
+		milestone = append(milestone, `t.milestone_id = ANY(`+q.arg(pq.Array(filter.MilestoneIds))+`)`)
+	}
+	if filter.NoMilestone {
+		milestone = append(milestone, `t.milestone_id IS NULL`)
+	}
+	if len(milestone) > 0 {
+		q.where(`(` + strings.Join(milestone, ` OR `) + `)`)
+	}
+
+	var sprint []string
+	if len(filter.SprintIds) > 0 {
+		sprint = append(sprint, `t.sprint_id = ANY(`+q.arg(pq.Array(filter.SprintIds))+`)`)
+	}
+	if filter.NoSprint {
+		sprint = append(sprint, `t.sprint_id IS NULL`)
+	}
+	if len(sprint) > 0 {
+		q.where(`(` + strings.Join(sprint, ` OR `) + `)`)
+	}
+
+	if len(filter.LabelIds) > 0 {
+		labelIds := q.arg(pq.Array(filter.LabelIds))
+		if filter.LabelMatch == models.LabelMatchAll {
+			q.where(`(SELECT COUNT(DISTINCT tl.label_id) FROM task_labels AS tl WHERE tl.task_id = t.id AND tl.label_id = ANY(` + labelIds + `)) = ` + q.arg(len(distinctIds(filter.LabelIds))))
+		} else {
+			q.where(`EXISTS (SELECT 1 FROM task_labels AS tl WHERE tl.task_id = t.id AND tl.label_id = ANY(` + labelIds + `))`)
+		}
+	}
+
+	for _, condition := range filter.CustomFields {
+		q.whereCustomField(condition)
+	}
+
+	for _, order := range filter.Sort {
+		direction := ` ASC`
+		if order.Desc {
+			direction = ` DESC`
+		}
+		q.order(q.sortExpression(order) + direction + ` NULLS LAST`)
+	}
+}
+
+// whereDateRange limits a date or timestamp column to the calendar days of the range.
+func (q *taskQuery) whereDateRange(column string, dates models.DateRange) {
+	if dates.From != nil {
+		q.where(column + ` >= ` + q.arg(dates.From.Format(models.DateLayout)) + `::date`)
+	}
+	if dates.To != nil {
+		q.where(column + ` < ` + q.arg(dates.To.Format(models.DateLayout)) + `::date + 1`)
+	}
+}
+
+func (q *taskQuery) sortExpression(order *models.TaskSort) string {
+	switch order.Key {
+	case models.SortByTitle:
+		return `LOWER(t.title)`
+	case models.SortByStatus:
+		return `LOWER(t.status)`
+	case models.SortByPriority:
+		// Well-known priorities sort by rank, anything else after them.
+		return `array_position(` + q.arg(pq.Array(models.GetPriorities())) + `::text[], LOWER(t.priority))`
+	case models.SortByDueDate:
+		return `t.due_date`
+	case models.SortByCreatedAt:
+		return `t.created_at`
+	case models.SortByUpdatedAt:
+		return `t.updated_at`
+	case models.SortByRank:
+		return `t.rank`
+	default:
+		return customFieldValue(order.CustomField, q.arg(order.CustomField.ID))
+	}
+}
+
+func (q *taskQuery) whereCustomField(condition *models.CustomFieldCondition) {
+	field := condition.Field
+	if field.Type == models.CustomFieldMultiSelect {
+		q.where(`EXISTS (SELECT 1 FROM task_custom_field_values AS v WHERE v.task_id = t.id AND v.field_id = ` + q.arg(field.ID) + ` AND v.value @> jsonb_build_array(` + q.arg(condition.Value) + `::text))`)
+		return
+	}
+
+	operator := ` = `
+	switch condition.Operator {
+	case models.CustomFieldFrom:
+		operator = ` >= `
+	case models.CustomFieldTo:
+		operator = ` <= `
+	}
+	value := customFieldValue(field, q.arg(field.ID))
+	q.where(value + operator + q.arg(condition.Value) + `::` + customFieldSQLType(field))
+}
+
+func (q *taskQuery) sql() string {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t`
+	if len(q.conditions) > 0 {
+		query += ` WHERE ` + strings.Join(q.conditions, ` AND `)
+	}
+	if len(q.orderBy) > 0 {
+		query += ` ORDER BY ` + strings.Join(q.orderBy, `, `) + `, t.created_at, t.id`
+	}
+	return query + `;`
+}
+
+// customFieldValue selects the value a task has for the custom field behind the placeholder, typed for comparison.
+func customFieldValue(field *models.CustomField, fieldId string) string {
+	return `(SELECT (v.value #>> '{}')::` + customFieldSQLType(field) + ` FROM task_custom_field_values AS v WHERE v.task_id = t.id AND v.field_id = ` + fieldId + `)`
+}
+
+func customFieldSQLType(field *models.CustomField) string {
+	switch field.Type {
+	case models.CustomFieldNumber:
+		return `numeric`
+	case models.CustomFieldDate:
+		return `date`
+	default:
+		return `text`
+	}
+}
+
+// assignedTo matches the tasks assigned to the user behind the placeholder.
+func assignedTo(userId string) string {
+	return `EXISTS (SELECT 1 FROM task_assignees AS a WHERE a.task_id = t.id AND a.user_id = ` + userId + `)`
+}
+
+func distinctIds(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var distinct []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			distinct = append(distinct, id)
+		}
+	}
+	return distinct
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
+
+// escapeLike makes the LIKE wildcards in a search term match literally.
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
@@ -2,17 +2,73 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"github.com/drTragger/MykroTask/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const taskColumns = `t.id, ` + taskKeyColumn + `, t.title, t.description, t.status, t.priority, t.due_date, t.project_id, t.created_by, t.created_at, t.updated_at, t.parent_id, t.milestone_id, t.sprint_id, t.estimate, t.version, t.rank, t.series_id, t.occurrence_at, ` + taskAssigneesColumn + `, ` + taskLabelsColumn + `, ` + taskCustomFieldsColumn
+
+const taskKeyColumn = `(SELECT p.key FROM projects AS p WHERE p.id = t.project_id) || '-' || t.number`
+
+const taskAssigneesColumn = `ARRAY(SELECT a.user_id FROM task_assignees AS a WHERE a.task_id = t.id ORDER BY a.assigned_at, a.user_id)`
+
+const taskLabelsColumn = `ARRAY(SELECT tl.label_id FROM task_labels AS tl WHERE tl.task_id = t.id ORDER BY tl.label_id)`
+
+const taskCustomFieldsColumn = `COALESCE((SELECT jsonb_object_agg(v.field_id, v.value) FROM task_custom_field_values AS v WHERE v.task_id = t.id), '{}')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(row rowScanner, t *models.Task) error {
+	var customFields []byte
+	err := row.Scan(&t.ID, &t.Key, &t.Title, &t.Description, &t.Status, &t.Priority, &t.DueDate, &t.ProjectID, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt, &t.ParentID, &t.MilestoneID, &t.SprintID, &t.Estimate, &t.Version, &t.Rank, &t.SeriesID, &t.OccurrenceAt, pq.Array(&t.Assignees), pq.Array(&t.Labels), &customFields)
+	if err != nil {
+		return err
+	}
+	t.CustomFields = nil
+	return json.Unmarshal(customFields, &t.CustomFields)
+}
+
+func scanTasks(rows *sql.Rows) ([]*models.Task, error) {
+	var tasks []*models.Task
+	for rows.Next() {
+		var t models.Task
+		err := scanTask(rows, &t)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &t)
+	}
+	return tasks, rows.Err()
+}
+
 type TaskRepository interface {
-	CreateTask(task *models.Task) (*models.Task, error)
-	GetTasksForUser(projectId, userId uuid.UUID) ([]*models.Task, error)
+	CreateTaskTx(tx *sql.Tx, task *models.Task) (*models.Task, error)
+	GetTasksForUser(projectId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, error)
+	GetTasksForUserForUpdateTx(tx *sql.Tx, projectId, userId uuid.UUID) ([]*models.Task, error)
 	GetTaskById(projectId, taskId uuid.UUID) (*models.Task, error)
-	DeleteTask(projectId, taskId uuid.UUID) error
-	GetTasksForProject(projectId, userId uuid.UUID) ([]*models.Task, error)
-	UpdateTask(task *models.Task) (*models.Task, error)
+	GetTaskByIdForUpdateTx(tx *sql.Tx, projectId, taskId uuid.UUID) (*models.Task, error)
+	GetTaskByKey(projectKey string, number int) (*models.Task, error)
+	GetSprintTasksForUpdateTx(tx *sql.Tx, sprintId uuid.UUID) ([]*models.Task, error)
+	DeleteTaskTx(tx *sql.Tx, projectId, taskId uuid.UUID) error
+	GetTasksForProject(projectId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, error)
+	UpdateTaskTx(tx *sql.Tx, task *models.Task) (*models.Task, error)
+	SetAssigneesTx(tx *sql.Tx, taskId uuid.UUID, userIds []uuid.UUID) ([]uuid.UUID, error)
+	SetCustomFieldValuesTx(tx *sql.Tx, taskId uuid.UUID, values models.CustomFieldValues) (models.CustomFieldValues, error)
+	GetLastRankTx(tx *sql.Tx, projectId uuid.UUID, status string, excludeId uuid.UUID) (string, error)
+	GetRankAfterTx(tx *sql.Tx, projectId uuid.UUID, status string, excludeId uuid.UUID, rank string) (string, error)
+	GetRankBeforeTx(tx *sql.Tx, projectId uuid.UUID, status string, excludeId uuid.UUID, rank string) (string, error)
+	GetColumnForUpdateTx(tx *sql.Tx, projectId uuid.UUID, status string) ([]uuid.UUID, error)
+	SetRanksTx(tx *sql.Tx, taskIds []uuid.UUID, ranks []string) error
+	GetChildren(projectId, taskId uuid.UUID) ([]*models.Task, error)
+	GetSubtree(projectId, taskId uuid.UUID) ([]*models.Task, error)
+	GetAncestorIds(taskId uuid.UUID) ([]uuid.UUID, error)
+	GetSubtreeHeight(taskId uuid.UUID) (int, error)
+	GetChildrenProgress(taskIds []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error)
 }
 
 type taskRepository struct {
@@ -23,86 +79,353 @@ func NewTaskRepository(db *sql.DB) TaskRepository {
 	return &taskRepository{db: db}
 }
 
-func (r *taskRepository) CreateTask(task *models.Task) (*models.Task, error) {
-	query := `INSERT INTO tasks(id, title, description, status, priority, assignee, due_date, project_id, created_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;`
-	row := r.db.QueryRow(query, task.ID, task.Title, task.Description, task.Status, task.Priority, task.Assignee, task.DueDate, task.ProjectID, task.CreatedBy)
+func (r *taskRepository) CreateTaskTx(tx *sql.Tx, task *models.Task) (*models.Task, error) {
+	// Bumping the project counter locks its row, so tasks created at the same time get consecutive numbers.
+	query := `WITH counter AS (UPDATE projects SET task_counter = task_counter + 1 WHERE id = $7 RETURNING task_counter)
+              INSERT INTO tasks AS t (id, title, description, status, priority, due_date, project_id, created_by, parent_id, milestone_id, sprint_id, estimate, rank, series_id, occurrence_at, number)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, (SELECT task_counter FROM counter)) RETURNING ` + taskColumns + `;`
+	row := tx.QueryRow(query, task.ID, task.Title, task.Description, task.Status, task.Priority, task.DueDate, task.ProjectID, task.CreatedBy, task.ParentID, task.MilestoneID, task.SprintID, task.Estimate, task.Rank, task.SeriesID, task.OccurrenceAt)
 
-	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority, &task.Assignee, &task.DueDate, &task.ProjectID, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt)
+	err := scanTask(row, task)
 	if err != nil {
 		return nil, err
 	}
 	return task, nil
 }
 
-func (r *taskRepository) GetTasksForUser(projectId, userId uuid.UUID) ([]*models.Task, error) {
-	var tasks []*models.Task
-	query := `SELECT * FROM tasks WHERE project_id = $1 AND assignee = $2;`
-	rows, err := r.db.Query(query, projectId, userId)
+func (r *taskRepository) GetTasksForUser(projectId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, error) {
+	q := newTaskQuery()
+	q.where(`t.project_id = ` + q.arg(projectId))
+	q.where(assignedTo(q.arg(userId)))
+	q.applyFilter(filter)
+
+	rows, err := r.db.Query(q.sql(), q.args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	for rows.Next() {
-		var t models.Task
-		err = rows.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.Assignee, &t.DueDate, &t.ProjectID, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		tasks = append(tasks, &t)
+	return scanTasks(rows)
+}
+
+// GetTasksForUserForUpdateTx loads the tasks assigned to the user and locks their rows until the transaction ends.
+func (r *taskRepository) GetTasksForUserForUpdateTx(tx *sql.Tx, projectId, userId uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.project_id = $1 AND ` + assignedTo("$2") + ` FOR UPDATE;`
+	rows, err := tx.Query(query, projectId, userId)
+	if err != nil {
+		return nil, err
 	}
-	return tasks, nil
+	defer rows.Close()
+
+	return scanTasks(rows)
 }
 
 func (r *taskRepository) GetTaskById(projectId, taskId uuid.UUID) (*models.Task, error) {
 	var t models.Task
-	query := `SELECT * FROM tasks WHERE id = $1 AND project_id = $2;`
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.id = $1 AND t.project_id = $2;`
 
 	row := r.db.QueryRow(query, taskId, projectId)
-	err := row.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.Assignee, &t.DueDate, &t.ProjectID, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
+	err := scanTask(row, &t)
 	if err != nil {
 		return nil, err
 	}
 	return &t, nil
 }
 
-func (r *taskRepository) DeleteTask(projectId, taskId uuid.UUID) error {
+// GetTaskByIdForUpdateTx loads a task and locks its row until the transaction ends.
+func (r *taskRepository) GetTaskByIdForUpdateTx(tx *sql.Tx, projectId, taskId uuid.UUID) (*models.Task, error) {
+	var t models.Task
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.id = $1 AND t.project_id = $2 FOR UPDATE;`
+
+	row := tx.QueryRow(query, taskId, projectId)
+	err := scanTask(row, &t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTaskByKey finds a task by its number and any key its project has ever used.
+func (r *taskRepository) GetTaskByKey(projectKey string, number int) (*models.Task, error) {
+	var t models.Task
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t JOIN project_keys AS k ON k.project_id = t.project_id WHERE k.key = $1 AND t.number = $2;`
+
+	row := r.db.QueryRow(query, projectKey, number)
+	err := scanTask(row, &t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetSprintTasksForUpdateTx loads the tasks of a sprint and locks their rows until the transaction ends.
+func (r *taskRepository) GetSprintTasksForUpdateTx(tx *sql.Tx, sprintId uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.sprint_id = $1 ORDER BY t.created_at, t.id FOR UPDATE;`
+	rows, err := tx.Query(query, sprintId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+func (r *taskRepository) DeleteTaskTx(tx *sql.Tx, projectId, taskId uuid.UUID) error {
 	query := `DELETE FROM tasks WHERE id = $1 AND project_id = $2;`
 
-	_, err := r.db.Exec(query, taskId, projectId)
+	_, err := tx.Exec(query, taskId, projectId)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *taskRepository) GetTasksForProject(projectId, userId uuid.UUID) ([]*models.Task, error) {
-	query := `SELECT * FROM tasks WHERE project_id = $1 AND assignee = $2;`
-	rows, err := r.db.Query(query, projectId, userId)
+func (r *taskRepository) GetTasksForProject(projectId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, error) {
+	q := newTaskQuery()
+	q.where(`t.project_id = ` + q.arg(projectId))
+	q.applyFilter(filter)
+
+	rows, err := r.db.Query(q.sql(), q.args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tasks []*models.Task
+	return scanTasks(rows)
+}
+
+func (r *taskRepository) UpdateTaskTx(tx *sql.Tx, task *models.Task) (*models.Task, error) {
+	query := `UPDATE tasks AS t SET title = $3, description = $4, status = $5, priority = $6, due_date = $7, parent_id = $8, milestone_id = $9, sprint_id = $10, estimate = $11, rank = $12, series_id = $13, occurrence_at = $14, updated_at = $15, version = t.version + 1 WHERE t.id = $1 AND t.project_id = $2 AND t.version = $16 RETURNING ` + taskColumns + `;`
+	row := tx.QueryRow(query, task.ID, task.ProjectID, task.Title, task.Description, task.Status, task.Priority, task.DueDate, task.ParentID, task.MilestoneID, task.SprintID, task.Estimate, task.Rank, task.SeriesID, task.OccurrenceAt, task.UpdatedAt, task.Version)
+
+	err := scanTask(row, task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// SetAssigneesTx replaces the assignees of a task and returns them in assignment order.
+// Users who stay assigned keep their original assignment time.
+func (r *taskRepository) SetAssigneesTx(tx *sql.Tx, taskId uuid.UUID, userIds []uuid.UUID) ([]uuid.UUID, error) {
+	if userIds == nil {
+		// A nil slice would be sent as NULL and match nothing.
+		userIds = []uuid.UUID{}
+	}
+
+	_, err := tx.Exec(`DELETE FROM task_assignees WHERE task_id = $1 AND NOT user_id = ANY($2);`, taskId, pq.Array(userIds))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO task_assignees (task_id, user_id) SELECT $1, UNNEST($2::uuid[]) ON CONFLICT DO NOTHING;`, taskId, pq.Array(userIds))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`SELECT user_id FROM task_assignees WHERE task_id = $1 ORDER BY assigned_at, user_id;`, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignees := make([]uuid.UUID, 0)
 	for rows.Next() {
-		var t models.Task
-		err = rows.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.Assignee, &t.DueDate, &t.ProjectID, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
+		var id uuid.UUID
+		err = rows.Scan(&id)
 		if err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, &t)
+		assignees = append(assignees, id)
 	}
-	return tasks, nil
+	return assignees, rows.Err()
 }
 
-func (r *taskRepository) UpdateTask(task *models.Task) (*models.Task, error) {
-	query := `UPDATE tasks SET title = $3, description = $4, status = $5, priority = $6, assignee = $7, due_date = $8 WHERE id = $1 AND project_id = $2 RETURNING *;`
-	row := r.db.QueryRow(query, task.ID, task.ProjectID, task.Title, task.Description, task.Status, task.Priority, task.Assignee, task.DueDate)
+// SetCustomFieldValuesTx replaces the custom field values of a task and returns them as stored.
+func (r *taskRepository) SetCustomFieldValuesTx(tx *sql.Tx, taskId uuid.UUID, values models.CustomFieldValues) (models.CustomFieldValues, error) {
+	fieldIds := make([]uuid.UUID, 0, len(values))
+	for fieldId := range values {
+		fieldIds = append(fieldIds, fieldId)
+	}
 
-	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority, &task.Assignee, &task.DueDate, &task.ProjectID, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt)
+	_, err := tx.Exec(`DELETE FROM task_custom_field_values WHERE task_id = $1 AND NOT field_id = ANY($2);`, taskId, pq.Array(fieldIds))
 	if err != nil {
 		return nil, err
 	}
-	return task, nil
+
+	for fieldId, value := range values {
+		query := `INSERT INTO task_custom_field_values (task_id, field_id, value) VALUES ($1, $2, $3) ON CONFLICT (task_id, field_id) DO UPDATE SET value = EXCLUDED.value;`
+		_, err = tx.Exec(query, taskId, fieldId, []byte(value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stored []byte
+	query := `SELECT COALESCE(jsonb_object_agg(field_id, value), '{}') FROM task_custom_field_values WHERE task_id = $1;`
+	err = tx.QueryRow(query, taskId).Scan(&stored)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.CustomFieldValues
+	err = json.Unmarshal(stored, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetLastRankTx returns the highest rank in a status column, or an empty rank for an empty column.
+func (r *taskRepository) GetLastRankTx(tx *sql.Tx, projectId uuid.UUID, status string, excludeId uuid.UUID) (string, error) {
+	query := `SELECT rank FROM tasks WHERE project_id = $1 AND status = $2 AND id <> $3 ORDER BY rank DESC LIMIT 1 FOR UPDATE;`
+	return r.getRankTx(tx, query, projectId, status, excludeId)
+}
+
+// GetRankAfterTx returns the lowest rank in a status column above the given one, or an empty rank if there is none.
+func (r *taskRepository) GetRankAfterTx(tx *sql.Tx, projectId uuid.UUID, status string, excludeId uuid.UUID, rank string) (string, error) {
+	query := `SELECT rank FROM tasks WHERE project_id = $1 AND status = $2 AND id <> $3 AND rank > $4 ORDER BY rank LIMIT 1 FOR UPDATE;`
+	return r.getRankTx(tx, query, projectId, status, excludeId, rank)
+}
+
+// GetRankBeforeTx returns the highest rank in a status column below the given one, or an empty rank if there is none.
+func (r *taskRepository) GetRankBeforeTx(tx *sql.Tx, projectId uuid.UUID, status string, excludeId uuid.UUID, rank string) (string, error) {
+	query := `SELECT rank FROM tasks WHERE project_id = $1 AND status = $2 AND id <> $3 AND rank < $4 ORDER BY rank DESC LIMIT 1 FOR UPDATE;`
+	return r.getRankTx(tx, query, projectId, status, excludeId, rank)
+}
+
+func (r *taskRepository) getRankTx(tx *sql.Tx, query string, args ...interface{}) (string, error) {
+	var rank string
+	err := tx.QueryRow(query, args...).Scan(&rank)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return rank, err
+}
+
+// GetColumnForUpdateTx returns the ids of a status column in board order and locks their rows until the transaction ends.
+func (r *taskRepository) GetColumnForUpdateTx(tx *sql.Tx, projectId uuid.UUID, status string) ([]uuid.UUID, error) {
+	query := `SELECT id FROM tasks WHERE project_id = $1 AND status = $2 ORDER BY rank, created_at, id FOR UPDATE;`
+	rows, err := tx.Query(query, projectId, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SetRanksTx gives each task the rank at the same position. It does not bump the task versions.
+func (r *taskRepository) SetRanksTx(tx *sql.Tx, taskIds []uuid.UUID, ranks []string) error {
+	query := `UPDATE tasks AS t SET rank = r.rank FROM UNNEST($1::uuid[], $2::text[]) AS r(id, rank) WHERE t.id = r.id;`
+	_, err := tx.Exec(query, pq.Array(taskIds), pq.Array(ranks))
+	return err
+}
+
+func (r *taskRepository) GetChildren(projectId, taskId uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.project_id = $1 AND t.parent_id = $2 ORDER BY t.created_at;`
+	rows, err := r.db.Query(query, projectId, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// GetSubtree returns the task itself followed by all of its descendants.
+func (r *taskRepository) GetSubtree(projectId, taskId uuid.UUID) ([]*models.Task, error) {
+	query := `WITH RECURSIVE subtree AS (
+                  SELECT id, 0 AS depth FROM tasks WHERE id = $2 AND project_id = $1
+                  UNION ALL
+                  SELECT c.id, s.depth + 1 FROM tasks AS c JOIN subtree AS s ON c.parent_id = s.id WHERE s.depth < $3
+              )
+              SELECT ` + taskColumns + ` FROM tasks AS t JOIN subtree AS s ON t.id = s.id ORDER BY s.depth, t.created_at;`
+	rows, err := r.db.Query(query, projectId, taskId, models.MaxTaskDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// GetAncestorIds returns the chain of ids from the task up to its root, starting with the task itself.
+func (r *taskRepository) GetAncestorIds(taskId uuid.UUID) ([]uuid.UUID, error) {
+	query := `WITH RECURSIVE ancestors AS (
+                  SELECT id, parent_id, 0 AS depth FROM tasks WHERE id = $1
+                  UNION ALL
+                  SELECT p.id, p.parent_id, a.depth + 1 FROM tasks AS p JOIN ancestors AS a ON p.id = a.parent_id WHERE a.depth <= $2
+              )
+              SELECT id FROM ancestors ORDER BY depth;`
+	rows, err := r.db.Query(query, taskId, models.MaxTaskDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetSubtreeHeight returns the number of levels below and including the task, 1 for a leaf.
+func (r *taskRepository) GetSubtreeHeight(taskId uuid.UUID) (int, error) {
+	query := `WITH RECURSIVE subtree AS (
+                  SELECT id, 1 AS height FROM tasks WHERE id = $1
+                  UNION ALL
+                  SELECT c.id, s.height + 1 FROM tasks AS c JOIN subtree AS s ON c.parent_id = s.id WHERE s.height <= $2
+              )
+              SELECT COALESCE(MAX(height), 0) FROM subtree;`
+
+	var height int
+	err := r.db.QueryRow(query, taskId, models.MaxTaskDepth).Scan(&height)
+	if err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+func (r *taskRepository) GetChildrenProgress(taskIds []uuid.UUID) (map[uuid.UUID]*models.TaskProgress, error) {
+	progress := make(map[uuid.UUID]*models.TaskProgress)
+	if len(taskIds) == 0 {
+		return progress, nil
+	}
+
+	query := `SELECT parent_id, COUNT(*), COUNT(*) FILTER (WHERE LOWER(status) = ANY($2)),
+                     COALESCE(SUM(estimate), 0), COALESCE(SUM(estimate) FILTER (WHERE LOWER(status) = ANY($2)), 0)
+              FROM tasks WHERE parent_id = ANY($1) GROUP BY parent_id;`
+	rows, err := r.db.Query(query, pq.Array(taskIds), pq.Array(models.GetDoneStatuses()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentId uuid.UUID
+		var total, done int
+		var estimate, doneEstimate float64
+		err = rows.Scan(&parentId, &total, &done, &estimate, &doneEstimate)
+		if err != nil {
+			return nil, err
+		}
+		progress[parentId] = models.NewTaskProgress(total, done, estimate, doneEstimate)
+	}
+	return progress, rows.Err()
 }
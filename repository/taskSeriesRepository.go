@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+const taskSeriesColumns = `ts.id, ts.project_id, ts.rule, ts.starts_at, ts.next_at, ts.last_occurrence_at, ts.occurrences, ts.title, ts.description, ts.status, ts.priority, ts.estimate, ts.assignees, ts.created_by, ts.created_at, ts.updated_at`
+
+func scanTaskSeries(row rowScanner, ts *models.TaskSeries) error {
+	return row.Scan(&ts.ID, &ts.ProjectId, &ts.Rule, &ts.StartsAt, &ts.NextAt, &ts.LastOccurrenceAt, &ts.Occurrences, &ts.Title, &ts.Description, &ts.Status, &ts.Priority, &ts.Estimate, pq.Array(&ts.Assignees), &ts.CreatedBy, &ts.CreatedAt, &ts.UpdatedAt)
+}
+
+type TaskSeriesRepository interface {
+	CreateSeriesTx(tx *sql.Tx, series *models.TaskSeries) (*models.TaskSeries, error)
+	GetSeriesById(projectId, seriesId uuid.UUID) (*models.TaskSeries, error)
+	GetSeriesByIdForUpdateTx(tx *sql.Tx, projectId, seriesId uuid.UUID) (*models.TaskSeries, error)
+	GetDueSeriesForUpdateTx(tx *sql.Tx, now time.Time, excludeIds []uuid.UUID) (*models.TaskSeries, error)
+	UpdateSeriesTx(tx *sql.Tx, series *models.TaskSeries) (*models.TaskSeries, error)
+	DeleteSeries(projectId, seriesId uuid.UUID) error
+	GetInstances(seriesId uuid.UUID) ([]*models.Task, error)
+	GetOpenInstancesForUpdateTx(tx *sql.Tx, seriesId uuid.UUID) ([]*models.Task, error)
+}
+
+type taskSeriesRepository struct {
+	db *sql.DB
+}
+
+func NewTaskSeriesRepository(db *sql.DB) TaskSeriesRepository {
+	return &taskSeriesRepository{db: db}
+}
+
+func (r *taskSeriesRepository) CreateSeriesTx(tx *sql.Tx, series *models.TaskSeries) (*models.TaskSeries, error) {
+	query := `INSERT INTO task_series AS ts (id, project_id, rule, starts_at, next_at, last_occurrence_at, occurrences, title, description, status, priority, estimate, assignees, created_by)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING ` + taskSeriesColumns + `;`
+	row := tx.QueryRow(query, series.ID, series.ProjectId, series.Rule, series.StartsAt, series.NextAt, series.LastOccurrenceAt, series.Occurrences, series.Title, series.Description, series.Status, series.Priority, series.Estimate, pq.Array(seriesAssignees(series)), series.CreatedBy)
+
+	err := scanTaskSeries(row, series)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (r *taskSeriesRepository) GetSeriesById(projectId, seriesId uuid.UUID) (*models.TaskSeries, error) {
+	var ts models.TaskSeries
+	query := `SELECT ` + taskSeriesColumns + ` FROM task_series AS ts WHERE ts.id = $1 AND ts.project_id = $2;`
+
+	row := r.db.QueryRow(query, seriesId, projectId)
+	err := scanTaskSeries(row, &ts)
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// GetSeriesByIdForUpdateTx loads a series and locks its row until the transaction ends.
+func (r *taskSeriesRepository) GetSeriesByIdForUpdateTx(tx *sql.Tx, projectId, seriesId uuid.UUID) (*models.TaskSeries, error) {
+	var ts models.TaskSeries
+	query := `SELECT ` + taskSeriesColumns + ` FROM task_series AS ts WHERE ts.id = $1 AND ts.project_id = $2 FOR UPDATE;`
+
+	row := tx.QueryRow(query, seriesId, projectId)
+	err := scanTaskSeries(row, &ts)
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// GetDueSeriesForUpdateTx locks one series whose next occurrence has come, other than the excluded ones.
+// Series locked by another transaction are skipped, so several schedulers can work through them side by side.
+func (r *taskSeriesRepository) GetDueSeriesForUpdateTx(tx *sql.Tx, now time.Time, excludeIds []uuid.UUID) (*models.TaskSeries, error) {
+	var ts models.TaskSeries
+	query := `SELECT ` + taskSeriesColumns + ` FROM task_series AS ts WHERE ts.next_at <= $1 AND NOT ts.id = ANY($2)
+              ORDER BY ts.next_at LIMIT 1 FOR UPDATE SKIP LOCKED;`
+
+	if excludeIds == nil {
+		excludeIds = []uuid.UUID{}
+	}
+	row := tx.QueryRow(query, now, pq.Array(excludeIds))
+	err := scanTaskSeries(row, &ts)
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+func (r *taskSeriesRepository) UpdateSeriesTx(tx *sql.Tx, series *models.TaskSeries) (*models.TaskSeries, error) {
+	query := `UPDATE task_series AS ts SET rule = $3, starts_at = $4, next_at = $5, last_occurrence_at = $6, occurrences = $7, title = $8, description = $9, status = $10, priority = $11, estimate = $12, assignees = $13, updated_at = $14
+              WHERE ts.id = $1 AND ts.project_id = $2 RETURNING ` + taskSeriesColumns + `;`
+	row := tx.QueryRow(query, series.ID, series.ProjectId, series.Rule, series.StartsAt, series.NextAt, series.LastOccurrenceAt, series.Occurrences, series.Title, series.Description, series.Status, series.Priority, series.Estimate, pq.Array(seriesAssignees(series)), series.UpdatedAt)
+
+	err := scanTaskSeries(row, series)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// DeleteSeries ends the recurrence. Its tasks stay and are no longer linked to it.
+func (r *taskSeriesRepository) DeleteSeries(projectId, seriesId uuid.UUID) error {
+	query := `DELETE FROM task_series WHERE id = $1 AND project_id = $2;`
+	result, err := r.db.Exec(query, seriesId, projectId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetInstances returns the tasks of a series in the order of their occurrences.
+func (r *taskSeriesRepository) GetInstances(seriesId uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.series_id = $1 ORDER BY t.occurrence_at, t.created_at;`
+	rows, err := r.db.Query(query, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = make([]*models.Task, 0)
+	}
+	return tasks, nil
+}
+
+// GetOpenInstancesForUpdateTx locks the tasks of a series that are not done yet.
+func (r *taskSeriesRepository) GetOpenInstancesForUpdateTx(tx *sql.Tx, seriesId uuid.UUID) ([]*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks AS t WHERE t.series_id = $1 AND NOT LOWER(t.status) = ANY($2) ORDER BY t.occurrence_at, t.created_at FOR UPDATE;`
+	rows, err := tx.Query(query, seriesId, pq.Array(models.GetDoneStatuses()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// seriesAssignees keeps a series without assignees from being saved as NULL.
+func seriesAssignees(series *models.TaskSeries) []uuid.UUID {
+	if series.Assignees == nil {
+		return []uuid.UUID{}
+	}
+	return series.Assignees
+}
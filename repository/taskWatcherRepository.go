@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type TaskWatcherRepository interface {
+	AddWatcher(taskId, userId uuid.UUID) (*models.TaskWatcher, error)
+	AddWatchersTx(tx *sql.Tx, taskId uuid.UUID, userIds []uuid.UUID) error
+	DeleteWatcher(taskId, userId uuid.UUID) error
+	DeleteProjectWatcherTx(tx *sql.Tx, projectId, userId uuid.UUID) error
+	GetWatchers(taskId uuid.UUID) ([]*models.TaskWatcher, error)
+	GetWatcherIds(taskId uuid.UUID) ([]uuid.UUID, error)
+}
+
+type taskWatcherRepository struct {
+	db *sql.DB
+}
+
+func NewTaskWatcherRepository(db *sql.DB) TaskWatcherRepository {
+	return &taskWatcherRepository{db: db}
+}
+
+// AddWatcher subscribes the user to the task, watching it again changes nothing.
+func (r *taskWatcherRepository) AddWatcher(taskId, userId uuid.UUID) (*models.TaskWatcher, error) {
+	query := `WITH w AS (
+                  INSERT INTO task_watchers (task_id, user_id) VALUES ($1, $2)
+                  ON CONFLICT (task_id, user_id) DO UPDATE SET created_at = task_watchers.created_at RETURNING *
+              )
+              SELECT w.task_id, w.user_id, w.created_at, u.id, u.name, u.email FROM w JOIN users AS u ON w.user_id = u.id;`
+
+	w := models.TaskWatcher{User: &models.User{}}
+	err := r.db.QueryRow(query, taskId, userId).Scan(&w.TaskId, &w.UserId, &w.CreatedAt, &w.User.ID, &w.User.Name, &w.User.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// AddWatchersTx subscribes the users to the task, skipping the ones already watching it.
+func (r *taskWatcherRepository) AddWatchersTx(tx *sql.Tx, taskId uuid.UUID, userIds []uuid.UUID) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	query := `INSERT INTO task_watchers (task_id, user_id) SELECT $1, UNNEST($2::uuid[]) ON CONFLICT DO NOTHING;`
+	_, err := tx.Exec(query, taskId, pq.Array(userIds))
+	return err
+}
+
+func (r *taskWatcherRepository) DeleteWatcher(taskId, userId uuid.UUID) error {
+	query := `DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2;`
+	_, err := r.db.Exec(query, taskId, userId)
+	return err
+}
+
+// DeleteProjectWatcherTx stops a user from watching any task of the project.
+func (r *taskWatcherRepository) DeleteProjectWatcherTx(tx *sql.Tx, projectId, userId uuid.UUID) error {
+	query := `DELETE FROM task_watchers AS w USING tasks AS t WHERE w.task_id = t.id AND t.project_id = $1 AND w.user_id = $2;`
+	_, err := tx.Exec(query, projectId, userId)
+	return err
+}
+
+func (r *taskWatcherRepository) GetWatchers(taskId uuid.UUID) ([]*models.TaskWatcher, error) {
+	query := `SELECT w.task_id, w.user_id, w.created_at, u.id, u.name, u.email FROM task_watchers AS w JOIN users AS u ON w.user_id = u.id
+              WHERE w.task_id = $1 ORDER BY w.created_at, u.name;`
+	rows, err := r.db.Query(query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchers := make([]*models.TaskWatcher, 0)
+	for rows.Next() {
+		w := models.TaskWatcher{User: &models.User{}}
+		err = rows.Scan(&w.TaskId, &w.UserId, &w.CreatedAt, &w.User.ID, &w.User.Name, &w.User.Email)
+		if err != nil {
+			return nil, err
+		}
+		watchers = append(watchers, &w)
+	}
+	return watchers, rows.Err()
+}
+
+func (r *taskWatcherRepository) GetWatcherIds(taskId uuid.UUID) ([]uuid.UUID, error) {
+	var userIds []uuid.UUID
+	query := `SELECT ARRAY(SELECT user_id FROM task_watchers WHERE task_id = $1 ORDER BY created_at, user_id);`
+	err := r.db.QueryRow(query, taskId).Scan(pq.Array(&userIds))
+	return userIds, err
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"time"
+)
+
+const workLogColumns = `w.id, w.task_id, w.project_id, w.user_id, w.started_at, w.duration, w.note, w.created_at, w.updated_at, u.id, u.name, u.email`
+
+func scanWorkLog(row rowScanner, w *models.WorkLog) error {
+	w.User = &models.User{}
+	err := row.Scan(&w.ID, &w.TaskId, &w.ProjectId, &w.UserId, &w.StartedAt, &w.Duration, &w.Note, &w.CreatedAt, &w.UpdatedAt, &w.User.ID, &w.User.Name, &w.User.Email)
+	if err != nil {
+		return err
+	}
+	w.Running = w.Duration == nil
+	return nil
+}
+
+// timeReportRange limits work logs to the ones started within $2 and $3, either may be NULL.
+const timeReportRange = `w.duration IS NOT NULL AND ($2::timestamptz IS NULL OR w.started_at >= $2) AND ($3::timestamptz IS NULL OR w.started_at < $3)`
+
+type WorkLogRepository interface {
+	CreateWorkLog(workLog *models.WorkLog) (*models.WorkLog, error)
+	GetWorkLogs(taskId uuid.UUID) ([]*models.WorkLog, error)
+	GetWorkLogById(taskId, workLogId uuid.UUID) (*models.WorkLog, error)
+	UpdateWorkLog(workLog *models.WorkLog) (*models.WorkLog, error)
+	DeleteWorkLog(taskId, workLogId uuid.UUID) error
+	GetRunningTimer(userId uuid.UUID) (*models.WorkLog, error)
+	StopTimer(userId uuid.UUID, stoppedAt time.Time) (*models.WorkLog, error)
+	GetProjectTimeReport(projectId uuid.UUID, query *models.TimeReportQuery) ([]*models.TimeReportRow, error)
+	GetUserTimeReport(userId uuid.UUID, query *models.TimeReportQuery) ([]*models.TimeReportRow, error)
+}
+
+type workLogRepository struct {
+	db *sql.DB
+}
+
+func NewWorkLogRepository(db *sql.DB) WorkLogRepository {
+	return &workLogRepository{db: db}
+}
+
+// CreateWorkLog saves a work log, one without a duration starts a timer.
+func (r *workLogRepository) CreateWorkLog(workLog *models.WorkLog) (*models.WorkLog, error) {
+	query := `WITH w AS (
+                  INSERT INTO work_logs (id, task_id, project_id, user_id, started_at, duration, note) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *
+              )
+              SELECT ` + workLogColumns + ` FROM w JOIN users AS u ON w.user_id = u.id;`
+	row := r.db.QueryRow(query, workLog.ID, workLog.TaskId, workLog.ProjectId, workLog.UserId, workLog.StartedAt, workLog.Duration, workLog.Note)
+
+	err := scanWorkLog(row, workLog)
+	if err != nil {
+		return nil, err
+	}
+	return workLog, nil
+}
+
+func (r *workLogRepository) GetWorkLogs(taskId uuid.UUID) ([]*models.WorkLog, error) {
+	query := `SELECT ` + workLogColumns + ` FROM work_logs AS w JOIN users AS u ON w.user_id = u.id WHERE w.task_id = $1 ORDER BY w.started_at, w.id;`
+	rows, err := r.db.Query(query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workLogs := make([]*models.WorkLog, 0)
+	for rows.Next() {
+		var w models.WorkLog
+		err = scanWorkLog(rows, &w)
+		if err != nil {
+			return nil, err
+		}
+		workLogs = append(workLogs, &w)
+	}
+	return workLogs, rows.Err()
+}
+
+func (r *workLogRepository) GetWorkLogById(taskId, workLogId uuid.UUID) (*models.WorkLog, error) {
+	var w models.WorkLog
+	query := `SELECT ` + workLogColumns + ` FROM work_logs AS w JOIN users AS u ON w.user_id = u.id WHERE w.id = $1 AND w.task_id = $2;`
+
+	row := r.db.QueryRow(query, workLogId, taskId)
+	err := scanWorkLog(row, &w)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// UpdateWorkLog saves a finished work log, a running timer is only changed by stopping it.
+func (r *workLogRepository) UpdateWorkLog(workLog *models.WorkLog) (*models.WorkLog, error) {
+	query := `WITH w AS (
+                  UPDATE work_logs SET started_at = $3, duration = $4, note = $5, updated_at = $6 WHERE id = $1 AND task_id = $2 AND duration IS NOT NULL RETURNING *
+              )
+              SELECT ` + workLogColumns + ` FROM w JOIN users AS u ON w.user_id = u.id;`
+	row := r.db.QueryRow(query, workLog.ID, workLog.TaskId, workLog.StartedAt, workLog.Duration, workLog.Note, workLog.UpdatedAt)
+
+	err := scanWorkLog(row, workLog)
+	if err != nil {
+		return nil, err
+	}
+	return workLog, nil
+}
+
+func (r *workLogRepository) DeleteWorkLog(taskId, workLogId uuid.UUID) error {
+	query := `DELETE FROM work_logs WHERE id = $1 AND task_id = $2;`
+	result, err := r.db.Exec(query, workLogId, taskId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *workLogRepository) GetRunningTimer(userId uuid.UUID) (*models.WorkLog, error) {
+	var w models.WorkLog
+	query := `SELECT ` + workLogColumns + ` FROM work_logs AS w JOIN users AS u ON w.user_id = u.id WHERE w.user_id = $1 AND w.duration IS NULL;`
+
+	row := r.db.QueryRow(query, userId)
+	err := scanWorkLog(row, &w)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// StopTimer turns the running timer of the user into a work log, counting every started minute.
+func (r *workLogRepository) StopTimer(userId uuid.UUID, stoppedAt time.Time) (*models.WorkLog, error) {
+	query := `WITH w AS (
+                  UPDATE work_logs SET duration = GREATEST(1, CEIL(EXTRACT(EPOCH FROM ($2 - started_at)) / 60)), updated_at = $2
+                  WHERE user_id = $1 AND duration IS NULL RETURNING *
+              )
+              SELECT ` + workLogColumns + ` FROM w JOIN users AS u ON w.user_id = u.id;`
+
+	var w models.WorkLog
+	row := r.db.QueryRow(query, userId, stoppedAt)
+	err := scanWorkLog(row, &w)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// GetProjectTimeReport totals the finished work logs of the project per task or per member, most time first.
+func (r *workLogRepository) GetProjectTimeReport(projectId uuid.UUID, query *models.TimeReportQuery) ([]*models.TimeReportRow, error) {
+	var sqlQuery string
+	switch query.GroupBy {
+	case models.TimeByMember:
+		sqlQuery = `SELECT u.id, '', u.name, SUM(w.duration) AS total FROM work_logs AS w JOIN users AS u ON w.user_id = u.id
+                    WHERE w.project_id = $1 AND ` + timeReportRange + ` GROUP BY u.id ORDER BY total DESC, u.name;`
+	default:
+		sqlQuery = `SELECT t.id, ` + taskKeyColumn + `, t.title, SUM(w.duration) AS total FROM work_logs AS w JOIN tasks AS t ON w.task_id = t.id
+                    WHERE w.project_id = $1 AND ` + timeReportRange + ` GROUP BY t.id ORDER BY total DESC, t.number;`
+	}
+	return r.getTimeReport(sqlQuery, projectId, query)
+}
+
+// GetUserTimeReport totals the finished work logs of every project the user is a member of, most time first.
+func (r *workLogRepository) GetUserTimeReport(userId uuid.UUID, query *models.TimeReportQuery) ([]*models.TimeReportRow, error) {
+	sqlQuery := `SELECT p.id, p.key, p.name, SUM(w.duration) AS total FROM work_logs AS w JOIN projects AS p ON w.project_id = p.id
+                 JOIN project_members AS pm ON pm.project_id = p.id AND pm.user_id = $1
+                 WHERE ` + timeReportRange + ` GROUP BY p.id ORDER BY total DESC, p.name;`
+	return r.getTimeReport(sqlQuery, userId, query)
+}
+
+func (r *workLogRepository) getTimeReport(sqlQuery string, id uuid.UUID, query *models.TimeReportQuery) ([]*models.TimeReportRow, error) {
+	// The range ends with the whole last day.
+	var to *time.Time
+	if query.Range.To != nil {
+		end := query.Range.To.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	rows, err := r.db.Query(sqlQuery, id, query.Range.From, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := make([]*models.TimeReportRow, 0)
+	for rows.Next() {
+		var row models.TimeReportRow
+		err = rows.Scan(&row.ID, &row.Key, &row.Name, &row.Duration)
+		if err != nil {
+			return nil, err
+		}
+		row.Hours = models.MinutesToHours(row.Duration)
+		report = append(report, &row)
+	}
+	return report, rows.Err()
+}
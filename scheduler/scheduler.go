@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is work the scheduler runs periodically. Jobs must be safe to run on several instances
+// of the application at once.
+type Job func(ctx context.Context) error
+
+type task struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+// Scheduler runs background jobs at fixed intervals, each in its own goroutine.
+// A run that fails is logged and retried at the next tick.
+type Scheduler struct {
+	tasks []*task
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job to run once the scheduler starts and then every interval.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.tasks = append(s.tasks, &task{name: name, interval: interval, job: job})
+}
+
+// Start runs the registered jobs until the context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, t := range s.tasks {
+		go t.run(ctx)
+	}
+}
+
+func (t *task) run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		err := t.job(ctx)
+		if err != nil {
+			log.Printf("scheduler: %s failed: %v", t.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/storage"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrAttachmentTooLarge       = errors.New("attachment exceeds the maximum allowed size")
+	ErrAttachmentTypeNotAllowed = errors.New("attachment type is not allowed")
+)
+
+type AttachmentService interface {
+	MaxSize() int64
+	UploadAttachment(projectId, taskId, userId uuid.UUID, fileName string, file io.ReadSeeker, size int64) (*models.Attachment, bool, error)
+	GetAttachments(projectId, taskId, userId uuid.UUID) ([]*models.Attachment, bool, error)
+	DownloadAttachment(projectId, taskId, attachmentId, userId uuid.UUID) (*models.Attachment, io.ReadCloser, bool, error)
+	DeleteAttachment(projectId, taskId, attachmentId, userId uuid.UUID) (bool, error)
+}
+
+type attachmentService struct {
+	attachmentRepository    repository.AttachmentRepository
+	taskRepository          repository.TaskRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	blobStore               storage.BlobStore
+	maxSize                 int64
+	allowedTypes            []string
+}
+
+func NewAttachmentService(attachmentRepository repository.AttachmentRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository, blobStore storage.BlobStore, maxSize int64, allowedTypes []string) AttachmentService {
+	return &attachmentService{
+		attachmentRepository:    attachmentRepository,
+		taskRepository:          taskRepository,
+		projectMemberRepository: projectMemberRepository,
+		blobStore:               blobStore,
+		maxSize:                 maxSize,
+		allowedTypes:            allowedTypes,
+	}
+}
+
+func (s *attachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *attachmentService) UploadAttachment(projectId, taskId, userId uuid.UUID, fileName string, file io.ReadSeeker, size int64) (*models.Attachment, bool, error) {
+	_, forbidden, err := s.checkAccess(projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	if size > s.maxSize {
+		return nil, false, ErrAttachmentTooLarge
+	}
+
+	// The type is sniffed from the content, the client supplied Content-Type is not trusted.
+	mtype, err := mimetype.DetectReader(file)
+	if err != nil {
+		return nil, false, err
+	}
+	if !s.isAllowed(mtype) {
+		return nil, false, fmt.Errorf("%w: %s", ErrAttachmentTypeNotAllowed, mtype.String())
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, false, err
+	}
+
+	attachmentId := uuid.New()
+	storageKey := fmt.Sprintf("projects/%s/tasks/%s/%s", projectId, taskId, attachmentId)
+	attachment := &models.Attachment{
+		ID:          attachmentId,
+		TaskId:      taskId,
+		ProjectId:   projectId,
+		UploadedBy:  &userId,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: mtype.String(),
+		Size:        size,
+		StorageKey:  storageKey,
+	}
+
+	err = s.blobStore.Put(storageKey, file, size, attachment.ContentType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	attachment, err = s.attachmentRepository.CreateAttachment(attachment)
+	if err != nil {
+		if deleteErr := s.blobStore.Delete(storageKey); deleteErr != nil {
+			log.Printf("Failed to clean up attachment blob %s: %v", attachmentId, deleteErr)
+		}
+		return nil, false, err
+	}
+	return attachment, false, nil
+}
+
+func (s *attachmentService) GetAttachments(projectId, taskId, userId uuid.UUID) ([]*models.Attachment, bool, error) {
+	_, forbidden, err := s.checkAccess(projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	attachments, err := s.attachmentRepository.GetAttachments(taskId)
+	return attachments, false, err
+}
+
+func (s *attachmentService) DownloadAttachment(projectId, taskId, attachmentId, userId uuid.UUID) (*models.Attachment, io.ReadCloser, bool, error) {
+	_, forbidden, err := s.checkAccess(projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, nil, forbidden, err
+	}
+
+	attachment, err := s.attachmentRepository.GetAttachmentById(taskId, attachmentId)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	content, err := s.blobStore.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return attachment, content, false, nil
+}
+
+func (s *attachmentService) DeleteAttachment(projectId, taskId, attachmentId, userId uuid.UUID) (bool, error) {
+	member, forbidden, err := s.checkAccess(projectId, taskId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	attachment, err := s.attachmentRepository.GetAttachmentById(taskId, attachmentId)
+	if err != nil {
+		return false, err
+	}
+
+	uploadedByUser := attachment.UploadedBy != nil && *attachment.UploadedBy == userId
+	if !uploadedByUser && !member.CanEditProject() {
+		return true, nil
+	}
+
+	err = s.attachmentRepository.DeleteAttachment(taskId, attachmentId)
+	if err != nil {
+		return false, err
+	}
+
+	// The row is gone already, a leftover blob is only wasted space.
+	err = s.blobStore.Delete(attachment.StorageKey)
+	if err != nil {
+		log.Printf("Failed to delete attachment blob %s: %v", attachment.ID, err)
+	}
+	return false, nil
+}
+
+// deleteBlobs removes the blobs of attachments whose rows were deleted with their task or project.
+// A leftover blob is only wasted space, so failures are logged.
+func deleteBlobs(blobStore storage.BlobStore, keys []string) {
+	for _, key := range keys {
+		err := blobStore.Delete(key)
+		if err != nil {
+			log.Printf("Failed to delete attachment blob %s: %v", key, err)
+		}
+	}
+}
+
+func (s *attachmentService) checkAccess(projectId, taskId, userId uuid.UUID) (*models.ProjectMember, bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	_, err = s.taskRepository.GetTaskById(projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+	return member, false, nil
+}
+
+func (s *attachmentService) isAllowed(mtype *mimetype.MIME) bool {
+	for _, allowed := range s.allowedTypes {
+		if mtype.Is(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func sanitizeFileName(fileName string) string {
+	fileName = filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	fileName = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, fileName)
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "attachment"
+	}
+	if len(fileName) > 255 {
+		fileName = fileName[len(fileName)-255:]
+	}
+	return fileName
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+type BurndownService interface {
+	GetSprintBurndown(projectId, sprintId, userId uuid.UUID) (*models.Burndown, bool, error)
+	GetMilestoneBurndown(projectId, milestoneId, userId uuid.UUID) (*models.Burndown, bool, error)
+}
+
+type burndownService struct {
+	burndownRepository      repository.BurndownRepository
+	sprintRepository        repository.SprintRepository
+	milestoneRepository     repository.MilestoneRepository
+	projectMemberRepository repository.ProjectMemberRepository
+}
+
+func NewBurndownService(burndownRepository repository.BurndownRepository, sprintRepository repository.SprintRepository, milestoneRepository repository.MilestoneRepository, projectMemberRepository repository.ProjectMemberRepository) BurndownService {
+	return &burndownService{
+		burndownRepository:      burndownRepository,
+		sprintRepository:        sprintRepository,
+		milestoneRepository:     milestoneRepository,
+		projectMemberRepository: projectMemberRepository,
+	}
+}
+
+func (s *burndownService) GetSprintBurndown(projectId, sprintId, userId uuid.UUID) (*models.Burndown, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	sprint, err := s.sprintRepository.GetSprintById(projectId, sprintId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrSprintNotFound
+		}
+		return nil, false, err
+	}
+
+	burndown, err := s.getBurndown(projectId, models.BurndownSprint, sprintId, sprint.StartDate, sprint.EndDate)
+	return burndown, false, err
+}
+
+// GetMilestoneBurndown follows a milestone from the day it was created until its due date.
+func (s *burndownService) GetMilestoneBurndown(projectId, milestoneId, userId uuid.UUID) (*models.Burndown, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	milestone, err := s.milestoneRepository.GetMilestoneById(projectId, milestoneId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrMilestoneNotFound
+		}
+		return nil, false, err
+	}
+
+	start := milestone.CreatedAt
+	if milestone.DueDate.Before(start) {
+		start = milestone.DueDate
+	}
+	burndown, err := s.getBurndown(projectId, models.BurndownMilestone, milestoneId, start, milestone.DueDate)
+	return burndown, false, err
+}
+
+// getBurndown rebuilds the scope at the end of every day by walking back from the current state of its tasks
+// through their activity history, so days without any activity get the state they were left in.
+// Days are calendar days in UTC.
+func (s *burndownService) getBurndown(projectId uuid.UUID, scope models.BurndownScope, scopeId uuid.UUID, start, end time.Time) (*models.Burndown, error) {
+	start, end = utcDate(start), utcDate(end)
+	burndown := &models.Burndown{
+		Scope:     scope,
+		ScopeId:   scopeId,
+		StartDate: start.Format(models.DateLayout),
+		EndDate:   end.Format(models.DateLayout),
+		Points:    []*models.BurndownPoint{},
+	}
+
+	days := int(end.Sub(start).Hours()/24) + 1
+	shown := days
+	if today := utcDate(time.Now()); today.Before(end) {
+		shown = int(today.Sub(start).Hours()/24) + 1
+	}
+	if shown <= 0 {
+		return burndown, nil
+	}
+
+	tasks, err := s.burndownRepository.GetScopeTasks(projectId, scope, scopeId)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.burndownRepository.GetScopeActivities(projectId, scope, scopeId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks deleted since then are only known from their history.
+	states := make(map[uuid.UUID]*burndownState)
+	for _, task := range tasks {
+		states[task.ID] = &burndownState{exists: true, status: task.Status, estimate: task.Estimate, scopeId: burndownScopeOf(task, scope)}
+	}
+	history := make(map[uuid.UUID][]*models.TaskActivity)
+	for _, activity := range activities {
+		history[activity.TaskId] = append(history[activity.TaskId], activity)
+		if states[activity.TaskId] == nil {
+			states[activity.TaskId] = &burndownState{}
+		}
+	}
+
+	for i := 0; i < shown; i++ {
+		burndown.Points = append(burndown.Points, &models.BurndownPoint{Date: start.AddDate(0, 0, i).Format(models.DateLayout)})
+	}
+
+	field := "sprintId"
+	if scope == models.BurndownMilestone {
+		field = "milestoneId"
+	}
+	for taskId, state := range states {
+		taskHistory := history[taskId]
+		next := len(taskHistory) - 1
+		for i := shown - 1; i >= 0; i-- {
+			dayEnd := start.AddDate(0, 0, i+1)
+			for ; next >= 0 && !taskHistory[next].CreatedAt.Before(dayEnd); next-- {
+				err = state.undo(taskHistory[next], field)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if state.exists && state.scopeId != nil && *state.scopeId == scopeId {
+				point := burndown.Points[i]
+				point.Total++
+				point.TotalEstimate += state.estimateValue()
+				if models.IsDoneStatus(state.status) {
+					point.Completed++
+					point.CompletedEstimate += state.estimateValue()
+				}
+			}
+		}
+	}
+
+	for i, point := range burndown.Points {
+		point.Remaining = point.Total - point.Completed
+		point.RemainingEstimate = point.TotalEstimate - point.CompletedEstimate
+		if days > 1 {
+			point.Ideal = float64(burndown.Points[0].Total) * float64(days-1-i) / float64(days-1)
+			point.IdealEstimate = burndown.Points[0].TotalEstimate * float64(days-1-i) / float64(days-1)
+		}
+	}
+	return burndown, nil
+}
+
+// burndownState is what a burndown needs to know about a task at some point of its history.
+type burndownState struct {
+	exists   bool
+	status   string
+	estimate *float64
+	scopeId  *uuid.UUID
+}
+
+func (st *burndownState) estimateValue() float64 {
+	if st.estimate == nil {
+		return 0
+	}
+	return *st.estimate
+}
+
+// undo rolls the state back to how it was before the activity.
+func (st *burndownState) undo(activity *models.TaskActivity, field string) error {
+	switch activity.Action {
+	case models.ActivityCreated:
+		st.exists = false
+		return nil
+	case models.ActivityDeleted:
+		st.exists = true
+	}
+
+	if change, ok := activity.Changes["status"]; ok {
+		err := json.Unmarshal(change.From, &st.status)
+		if err != nil {
+			return err
+		}
+	}
+	if change, ok := activity.Changes["estimate"]; ok {
+		st.estimate = nil
+		err := json.Unmarshal(change.From, &st.estimate)
+		if err != nil {
+			return err
+		}
+	}
+	if change, ok := activity.Changes[field]; ok {
+		st.scopeId = nil
+		err := json.Unmarshal(change.From, &st.scopeId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func burndownScopeOf(task *models.Task, scope models.BurndownScope) *uuid.UUID {
+	if scope == models.BurndownMilestone {
+		return task.MilestoneID
+	}
+	return task.SprintID
+}
+
+func utcDate(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
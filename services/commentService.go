@@ -0,0 +1,219 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"log"
+	"time"
+)
+
+var ErrInvalidReplyParent = errors.New("replies can only be made to top-level comments on the same task")
+
+type CommentService interface {
+	CreateComment(projectId, taskId, userId uuid.UUID, dto *models.CommentDTO) (*models.Comment, bool, error)
+	GetComments(projectId, taskId, userId uuid.UUID) ([]*models.Comment, bool, error)
+	UpdateComment(projectId, taskId, commentId, userId uuid.UUID, dto *models.CommentDTO) (*models.Comment, bool, error)
+	DeleteComment(projectId, taskId, commentId, userId uuid.UUID) (bool, error)
+}
+
+type commentService struct {
+	commentRepository       repository.CommentRepository
+	taskRepository          repository.TaskRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	taskWatcherRepository   repository.TaskWatcherRepository
+	mentionService          MentionService
+	notificationService     NotificationService
+	db                      *sql.DB
+}
+
+func NewCommentService(commentRepository repository.CommentRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository, taskWatcherRepository repository.TaskWatcherRepository, mentionService MentionService, notificationService NotificationService, db *sql.DB) CommentService {
+	return &commentService{
+		commentRepository:       commentRepository,
+		taskRepository:          taskRepository,
+		projectMemberRepository: projectMemberRepository,
+		taskWatcherRepository:   taskWatcherRepository,
+		mentionService:          mentionService,
+		notificationService:     notificationService,
+		db:                      db,
+	}
+}
+
+func (s *commentService) CreateComment(projectId, taskId, userId uuid.UUID, dto *models.CommentDTO) (*models.Comment, bool, error) {
+	task, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	if dto.ParentId != nil {
+		parent, err := s.commentRepository.GetCommentById(taskId, *dto.ParentId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, false, ErrInvalidReplyParent
+			}
+			return nil, false, err
+		}
+		if parent.ParentId != nil {
+			return nil, false, ErrInvalidReplyParent
+		}
+	}
+
+	comment := &models.Comment{
+		ID:       uuid.New(),
+		TaskId:   taskId,
+		AuthorId: userId,
+		ParentId: dto.ParentId,
+		Body:     dto.Body,
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	comment, err = s.commentRepository.CreateCommentTx(tx, comment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var mentioned []uuid.UUID
+	comment.Mentions, comment.UnresolvedMentions, mentioned, err = s.mentionService.SyncMentionsTx(tx, task, &comment.ID, userId, comment.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mentionService.NotifyMentioned(task, &comment.ID, userId, mentioned)
+	s.notifyCommented(task, comment, userId)
+	return comment, false, nil
+}
+
+// notifyCommented tells the watchers of the task about a new comment. The comment is already saved,
+// so a failure is only logged.
+func (s *commentService) notifyCommented(task *models.Task, comment *models.Comment, userId uuid.UUID) {
+	watchers, err := s.taskWatcherRepository.GetWatcherIds(task.ID)
+	if err != nil {
+		log.Printf("Failed to get the watchers of task %s: %v", task.ID, err)
+		return
+	}
+
+	// Mentioned users already heard about the comment.
+	mentioned := make(map[uuid.UUID]bool, len(comment.Mentions))
+	for _, mention := range comment.Mentions {
+		mentioned[mention.UserId] = true
+	}
+	var recipients []uuid.UUID
+	for _, watcher := range watchers {
+		if !mentioned[watcher] {
+			recipients = append(recipients, watcher)
+		}
+	}
+	err = s.notificationService.NotifyCommented(task, comment.ID, userId, recipients)
+	if err != nil {
+		log.Printf("Failed to send comment notifications for task %s: %v", task.ID, err)
+	}
+}
+
+// GetComments returns the top-level comments of a task with their replies nested under them.
+func (s *commentService) GetComments(projectId, taskId, userId uuid.UUID) ([]*models.Comment, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	comments, err := s.commentRepository.GetComments(taskId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	byId := make(map[uuid.UUID]*models.Comment, len(comments))
+	threads := make([]*models.Comment, 0, len(comments))
+	for _, c := range comments {
+		if c.ParentId == nil {
+			byId[c.ID] = c
+			threads = append(threads, c)
+		}
+	}
+	for _, c := range comments {
+		if c.ParentId == nil {
+			continue
+		}
+		if parent, ok := byId[*c.ParentId]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+	return threads, false, nil
+}
+
+func (s *commentService) UpdateComment(projectId, taskId, commentId, userId uuid.UUID, dto *models.CommentDTO) (*models.Comment, bool, error) {
+	task, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	comment, err := s.commentRepository.GetCommentById(taskId, commentId)
+	if err != nil {
+		return nil, false, err
+	}
+	if comment.AuthorId != userId {
+		return nil, true, nil
+	}
+
+	comment.Body = dto.Body
+	comment.UpdatedAt = time.Now()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	comment, err = s.commentRepository.UpdateCommentTx(tx, comment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var mentioned []uuid.UUID
+	comment.Mentions, comment.UnresolvedMentions, mentioned, err = s.mentionService.SyncMentionsTx(tx, task, &comment.ID, userId, comment.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mentionService.NotifyMentioned(task, &comment.ID, userId, mentioned)
+	return comment, false, nil
+}
+
+func (s *commentService) DeleteComment(projectId, taskId, commentId, userId uuid.UUID) (bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	comment, err := s.commentRepository.GetCommentById(taskId, commentId)
+	if err != nil {
+		return false, err
+	}
+	if comment.AuthorId != userId {
+		return true, nil
+	}
+
+	return false, s.commentRepository.DeleteComment(taskId, commentId)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrCustomFieldNotFound     = errors.New("custom field not found in this project")
+	ErrCustomFieldExists       = errors.New("custom field with this name already exists")
+	ErrCustomFieldOptions      = errors.New("only select fields have options and they need at least one")
+	ErrInvalidCustomFieldValue = errors.New("invalid custom field value")
+)
+
+type CustomFieldService interface {
+	CreateCustomField(projectId, userId uuid.UUID, dto *models.CreateCustomFieldDTO) (*models.CustomField, bool, error)
+	GetCustomFields(projectId, userId uuid.UUID) ([]*models.CustomField, bool, error)
+	UpdateCustomField(projectId, fieldId, userId uuid.UUID, dto *models.UpdateCustomFieldDTO) (*models.CustomField, bool, error)
+	DeleteCustomField(projectId, fieldId, userId uuid.UUID) (bool, error)
+}
+
+type customFieldService struct {
+	customFieldRepository   repository.CustomFieldRepository
+	projectMemberRepository repository.ProjectMemberRepository
+}
+
+func NewCustomFieldService(customFieldRepository repository.CustomFieldRepository, projectMemberRepository repository.ProjectMemberRepository) CustomFieldService {
+	return &customFieldService{
+		customFieldRepository:   customFieldRepository,
+		projectMemberRepository: projectMemberRepository,
+	}
+}
+
+func (s *customFieldService) CreateCustomField(projectId, userId uuid.UUID, dto *models.CreateCustomFieldDTO) (*models.CustomField, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	field := &models.CustomField{
+		ID:        uuid.New(),
+		ProjectId: projectId,
+		Name:      dto.Name,
+		Type:      dto.Type,
+		Options:   dto.Options,
+	}
+	err = validateCustomFieldOptions(field)
+	if err != nil {
+		return nil, false, err
+	}
+
+	field, err = s.customFieldRepository.CreateCustomField(field)
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrCustomFieldExists
+		}
+		return nil, false, err
+	}
+	return field, false, nil
+}
+
+func (s *customFieldService) GetCustomFields(projectId, userId uuid.UUID) ([]*models.CustomField, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	fields, err := s.customFieldRepository.GetCustomFields(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+	return fields, false, nil
+}
+
+// UpdateCustomField renames a field or changes its options. Values using a removed option are kept as they are.
+func (s *customFieldService) UpdateCustomField(projectId, fieldId, userId uuid.UUID, dto *models.UpdateCustomFieldDTO) (*models.CustomField, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	fields, err := s.customFieldRepository.GetCustomFields(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+	field := findCustomField(fields, fieldId)
+	if field == nil {
+		return nil, false, ErrCustomFieldNotFound
+	}
+
+	field.Name = dto.Name
+	field.Options = dto.Options
+	field.UpdatedAt = time.Now()
+	err = validateCustomFieldOptions(field)
+	if err != nil {
+		return nil, false, err
+	}
+
+	field, err = s.customFieldRepository.UpdateCustomField(field)
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrCustomFieldExists
+		}
+		return nil, false, err
+	}
+	return field, false, nil
+}
+
+// DeleteCustomField removes the field along with the values tasks have for it.
+func (s *customFieldService) DeleteCustomField(projectId, fieldId, userId uuid.UUID) (bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	err = s.customFieldRepository.DeleteCustomField(projectId, fieldId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrCustomFieldNotFound
+	}
+	return false, err
+}
+
+func (s *customFieldService) checkCanEdit(projectId, userId uuid.UUID) (bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+	return !member.CanEditProject(), nil
+}
+
+func validateCustomFieldOptions(field *models.CustomField) error {
+	if field.Options == nil {
+		field.Options = []string{}
+	}
+	if field.Type.HasOptions() != (len(field.Options) > 0) {
+		return ErrCustomFieldOptions
+	}
+	return nil
+}
+
+func findCustomField(fields []*models.CustomField, fieldId uuid.UUID) *models.CustomField {
+	for _, field := range fields {
+		if field.ID == fieldId {
+			return field
+		}
+	}
+	return nil
+}
+
+// validateCustomFieldValue checks a task's value against the type of its field.
+// isMember tells whether a user can be picked in a user field.
+func validateCustomFieldValue(field *models.CustomField, value json.RawMessage, isMember func(uuid.UUID) (bool, error)) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s %s", ErrInvalidCustomFieldValue, field.Name, reason)
+	}
+
+	switch field.Type {
+	case models.CustomFieldNumber:
+		var number float64
+		if json.Unmarshal(value, &number) != nil {
+			return invalid("must be a number")
+		}
+		return nil
+	case models.CustomFieldMultiSelect:
+		var options []string
+		if json.Unmarshal(value, &options) != nil {
+			return invalid("must be a list of options")
+		}
+		seen := make(map[string]bool)
+		for _, option := range options {
+			if !field.HasOption(option) {
+				return invalid(strconv.Quote(option) + " is not an option")
+			}
+			if seen[option] {
+				return invalid(strconv.Quote(option) + " is selected twice")
+			}
+			seen[option] = true
+		}
+		return nil
+	}
+
+	var text string
+	if json.Unmarshal(value, &text) != nil {
+		return invalid("must be a string")
+	}
+	switch field.Type {
+	case models.CustomFieldText:
+		if utf8.RuneCountInString(text) > models.MaxCustomFieldTextLength {
+			return invalid("is too long")
+		}
+	case models.CustomFieldDate:
+		_, err := time.Parse(models.DateLayout, text)
+		if err != nil {
+			return invalid("must be a date like " + models.DateLayout)
+		}
+	case models.CustomFieldSingleSelect:
+		if !field.HasOption(text) {
+			return invalid(strconv.Quote(text) + " is not an option")
+		}
+	case models.CustomFieldUser:
+		userId, err := uuid.Parse(text)
+		if err != nil {
+			return invalid("must be a user id")
+		}
+		member, err := isMember(userId)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return invalid("must be a member of this project")
+		}
+	}
+	return nil
+}
+
+// validateCustomFieldFilterValue checks a value a task list is filtered by against the type of its field.
+func validateCustomFieldFilterValue(field *models.CustomField, value string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s %s", ErrInvalidTaskFilter, field.Name, reason)
+	}
+
+	switch field.Type {
+	case models.CustomFieldNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return invalid("must be a number")
+		}
+	case models.CustomFieldDate:
+		_, err := time.Parse(models.DateLayout, value)
+		if err != nil {
+			return invalid("must be a date like " + models.DateLayout)
+		}
+	case models.CustomFieldUser:
+		_, err := uuid.Parse(value)
+		if err != nil {
+			return invalid("must be a user id")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"errors"
+	"github.com/lib/pq"
+)
+
+// ErrVersionConflict is returned when an update was based on a stale version of a resource.
+// The service returns the current state of the resource along with it.
+var ErrVersionConflict = errors.New("resource has been modified since it was read")
+
+// ValidationError wraps the validation failures of a request that could only be validated in the service.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
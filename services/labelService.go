@@ -0,0 +1,209 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrLabelNotFound        = errors.New("label not found in this project")
+	ErrLabelExists          = errors.New("label with this name already exists")
+	ErrLabelAlreadyAttached = errors.New("label is already attached to this task")
+	ErrLabelNotAttached     = errors.New("label is not attached to this task")
+)
+
+type LabelService interface {
+	CreateLabel(projectId, userId uuid.UUID, dto *models.LabelDTO) (*models.Label, bool, error)
+	GetLabels(projectId, userId uuid.UUID) ([]*models.Label, bool, error)
+	UpdateLabel(projectId, labelId, userId uuid.UUID, dto *models.LabelDTO) (*models.Label, bool, error)
+	DeleteLabel(projectId, labelId, userId uuid.UUID) (bool, error)
+	AttachLabel(projectId, taskId, labelId, userId uuid.UUID) (*models.Task, bool, error)
+	DetachLabel(projectId, taskId, labelId, userId uuid.UUID) (*models.Task, bool, error)
+}
+
+type labelService struct {
+	labelRepository         repository.LabelRepository
+	taskRepository          repository.TaskRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	taskActivityRepository  repository.TaskActivityRepository
+	db                      *sql.DB
+}
+
+func NewLabelService(labelRepository repository.LabelRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository, taskActivityRepository repository.TaskActivityRepository, db *sql.DB) LabelService {
+	return &labelService{
+		labelRepository:         labelRepository,
+		taskRepository:          taskRepository,
+		projectMemberRepository: projectMemberRepository,
+		taskActivityRepository:  taskActivityRepository,
+		db:                      db,
+	}
+}
+
+func (s *labelService) CreateLabel(projectId, userId uuid.UUID, dto *models.LabelDTO) (*models.Label, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	label, err := s.labelRepository.CreateLabel(&models.Label{
+		ID:        uuid.New(),
+		ProjectId: projectId,
+		Name:      dto.Name,
+		Color:     dto.Color,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrLabelExists
+		}
+		return nil, false, err
+	}
+	return label, false, nil
+}
+
+func (s *labelService) GetLabels(projectId, userId uuid.UUID) ([]*models.Label, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	labels, err := s.labelRepository.GetLabels(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+	return labels, false, nil
+}
+
+func (s *labelService) UpdateLabel(projectId, labelId, userId uuid.UUID, dto *models.LabelDTO) (*models.Label, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	label, err := s.labelRepository.UpdateLabel(&models.Label{
+		ID:        labelId,
+		ProjectId: projectId,
+		Name:      dto.Name,
+		Color:     dto.Color,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrLabelNotFound
+		}
+		if isUniqueViolation(err) {
+			err = ErrLabelExists
+		}
+		return nil, false, err
+	}
+	return label, false, nil
+}
+
+// DeleteLabel removes the label from the project and from every task carrying it.
+func (s *labelService) DeleteLabel(projectId, labelId, userId uuid.UUID) (bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	err = s.labelRepository.DeleteLabel(projectId, labelId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrLabelNotFound
+	}
+	return false, err
+}
+
+func (s *labelService) AttachLabel(projectId, taskId, labelId, userId uuid.UUID) (*models.Task, bool, error) {
+	return s.changeLabels(projectId, taskId, labelId, userId, func(tx *sql.Tx) error {
+		err := s.labelRepository.AttachLabelTx(tx, taskId, labelId)
+		if isUniqueViolation(err) {
+			return ErrLabelAlreadyAttached
+		}
+		return err
+	})
+}
+
+func (s *labelService) DetachLabel(projectId, taskId, labelId, userId uuid.UUID) (*models.Task, bool, error) {
+	return s.changeLabels(projectId, taskId, labelId, userId, func(tx *sql.Tx) error {
+		err := s.labelRepository.DetachLabelTx(tx, taskId, labelId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrLabelNotAttached
+		}
+		return err
+	})
+}
+
+// changeLabels runs a change of the task's labels and bumps the task version, so that the change
+// shows up in its history and invalidates the ETags handed out before.
+func (s *labelService) changeLabels(projectId, taskId, labelId, userId uuid.UUID, change func(tx *sql.Tx) error) (*models.Task, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	_, err = s.labelRepository.GetLabelById(projectId, labelId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, ErrLabelNotFound
+		}
+		return nil, false, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	before, err := s.taskRepository.GetTaskByIdForUpdateTx(tx, projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = change(tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	after := *before
+	after.UpdatedAt = time.Now()
+	task, err := s.taskRepository.UpdateTaskTx(tx, &after)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, userId, before, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+	return task, false, nil
+}
+
+func (s *labelService) checkCanEdit(projectId, userId uuid.UUID) (bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+	return !member.CanEditProject(), nil
+}
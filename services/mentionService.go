@@ -0,0 +1,175 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"log"
+	"strings"
+)
+
+type MentionService interface {
+	SyncMentionsTx(tx *sql.Tx, task *models.Task, commentId *uuid.UUID, actorId uuid.UUID, text string) ([]*models.Mention, []*models.UnresolvedMention, []uuid.UUID, error)
+	NotifyMentioned(task *models.Task, commentId *uuid.UUID, actorId uuid.UUID, userIds []uuid.UUID)
+}
+
+type mentionService struct {
+	mentionRepository       repository.MentionRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	notificationService     NotificationService
+}
+
+func NewMentionService(mentionRepository repository.MentionRepository, projectMemberRepository repository.ProjectMemberRepository, notificationService NotificationService) MentionService {
+	return &mentionService{
+		mentionRepository:       mentionRepository,
+		projectMemberRepository: projectMemberRepository,
+		notificationService:     notificationService,
+	}
+}
+
+// SyncMentionsTx replaces the stored mentions of a task description (commentId is nil) or of a comment
+// with the ones found in text. It also returns the members who were not mentioned there before,
+// to be passed to NotifyMentioned once the transaction is committed.
+func (s *mentionService) SyncMentionsTx(tx *sql.Tx, task *models.Task, commentId *uuid.UUID, actorId uuid.UUID, text string) ([]*models.Mention, []*models.UnresolvedMention, []uuid.UUID, error) {
+	previous, err := s.mentionRepository.GetMentions(task.ID, commentId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	alreadyMentioned := make(map[uuid.UUID]bool, len(previous))
+	for _, m := range previous {
+		alreadyMentioned[m.UserId] = true
+	}
+
+	handles := utils.ExtractMentions(text)
+	if len(handles) == 0 && len(previous) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	members, err := s.projectMemberRepository.GetMembers(task.ProjectID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = s.mentionRepository.DeleteMentionsTx(tx, task.ID, commentId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var mentions []*models.Mention
+	var unresolved []*models.UnresolvedMention
+	var newlyMentioned []uuid.UUID
+	mentioned := make(map[uuid.UUID]bool)
+	for _, handle := range handles {
+		member, reason := resolveMention(handle, members)
+		if member == nil {
+			unresolved = append(unresolved, &models.UnresolvedMention{Handle: handle, Reason: reason})
+			continue
+		}
+		if mentioned[member.UserId] {
+			continue
+		}
+		mentioned[member.UserId] = true
+
+		mention, err := s.mentionRepository.CreateMentionTx(tx, &models.Mention{
+			ID:          uuid.New(),
+			ProjectId:   task.ProjectID,
+			TaskId:      task.ID,
+			CommentId:   commentId,
+			UserId:      member.UserId,
+			MentionedBy: &actorId,
+			Handle:      handle,
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		mentions = append(mentions, mention)
+
+		if member.UserId != actorId && !alreadyMentioned[member.UserId] {
+			newlyMentioned = append(newlyMentioned, member.UserId)
+		}
+	}
+	return mentions, unresolved, newlyMentioned, nil
+}
+
+// NotifyMentioned tells the given users that they were mentioned. It runs after the mentions are committed,
+// so a failure is only logged.
+func (s *mentionService) NotifyMentioned(task *models.Task, commentId *uuid.UUID, actorId uuid.UUID, userIds []uuid.UUID) {
+	if len(userIds) == 0 {
+		return
+	}
+
+	members, err := s.projectMemberRepository.GetMembers(task.ProjectID)
+	if err != nil {
+		log.Printf("Failed to send mention notifications for task %s: %v", task.ID, err)
+		return
+	}
+	message := mentionMessage(task, commentId, actorId, members)
+
+	for _, userId := range userIds {
+		err = s.notificationService.Notify(&models.Notification{
+			UserId:    userId,
+			Type:      models.NotificationMention,
+			ProjectId: &task.ProjectID,
+			TaskId:    &task.ID,
+			CommentId: commentId,
+			ActorId:   &actorId,
+			Message:   message,
+		})
+		if err != nil {
+			log.Printf("Failed to send the mention notification to user %s: %v", userId, err)
+		}
+	}
+}
+
+// resolveMention matches a handle against the e-mail local part, the full name without spaces
+// and finally the first name of the project members. A handle has to match exactly one member.
+func resolveMention(handle string, members []*models.ProjectMember) (*models.ProjectMember, string) {
+	matchers := []func(m *models.ProjectMember) string{
+		func(m *models.ProjectMember) string {
+			return strings.SplitN(m.Email, "@", 2)[0]
+		},
+		func(m *models.ProjectMember) string {
+			return strings.Join(strings.Fields(m.Name), "")
+		},
+		func(m *models.ProjectMember) string {
+			fields := strings.Fields(m.Name)
+			if len(fields) == 0 {
+				return ""
+			}
+			return fields[0]
+		},
+	}
+
+	for _, matcher := range matchers {
+		var matches []*models.ProjectMember
+		for _, m := range members {
+			if strings.ToLower(matcher(m)) == handle {
+				matches = append(matches, m)
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], ""
+		}
+		if len(matches) > 1 {
+			return nil, models.MentionAmbiguous
+		}
+	}
+	return nil, models.MentionNotAMember
+}
+
+func mentionMessage(task *models.Task, commentId *uuid.UUID, actorId uuid.UUID, members []*models.ProjectMember) string {
+	actor := "Someone"
+	for _, m := range members {
+		if m.UserId == actorId {
+			actor = m.Name
+			break
+		}
+	}
+	if commentId != nil {
+		return fmt.Sprintf("%s mentioned you in a comment on \"%s\".", actor, task.Title)
+	}
+	return fmt.Sprintf("%s mentioned you in \"%s\".", actor, task.Title)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrMilestoneNotFound = errors.New("milestone not found in this project")
+
+type MilestoneService interface {
+	CreateMilestone(projectId, userId uuid.UUID, dto *models.MilestoneDTO) (*models.Milestone, bool, error)
+	GetMilestones(projectId, userId uuid.UUID) ([]*models.Milestone, bool, error)
+	GetMilestone(projectId, milestoneId, userId uuid.UUID) (*models.Milestone, bool, error)
+	UpdateMilestone(projectId, milestoneId, userId uuid.UUID, dto *models.MilestoneDTO) (*models.Milestone, bool, error)
+	DeleteMilestone(projectId, milestoneId, userId uuid.UUID) (bool, error)
+}
+
+type milestoneService struct {
+	milestoneRepository     repository.MilestoneRepository
+	projectMemberRepository repository.ProjectMemberRepository
+}
+
+func NewMilestoneService(milestoneRepository repository.MilestoneRepository, projectMemberRepository repository.ProjectMemberRepository) MilestoneService {
+	return &milestoneService{
+		milestoneRepository:     milestoneRepository,
+		projectMemberRepository: projectMemberRepository,
+	}
+}
+
+func (s *milestoneService) CreateMilestone(projectId, userId uuid.UUID, dto *models.MilestoneDTO) (*models.Milestone, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	milestone, err := s.milestoneRepository.CreateMilestone(&models.Milestone{
+		ID:          uuid.New(),
+		ProjectId:   projectId,
+		Name:        dto.Name,
+		Description: dto.Description,
+		DueDate:     dto.DueDate,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(milestone)
+	return milestone, false, err
+}
+
+func (s *milestoneService) GetMilestones(projectId, userId uuid.UUID) ([]*models.Milestone, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	milestones, err := s.milestoneRepository.GetMilestones(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(milestones...)
+	return milestones, false, err
+}
+
+func (s *milestoneService) GetMilestone(projectId, milestoneId, userId uuid.UUID) (*models.Milestone, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	milestone, err := s.milestoneRepository.GetMilestoneById(projectId, milestoneId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrMilestoneNotFound
+		}
+		return nil, false, err
+	}
+
+	err = s.attachProgress(milestone)
+	return milestone, false, err
+}
+
+func (s *milestoneService) UpdateMilestone(projectId, milestoneId, userId uuid.UUID, dto *models.MilestoneDTO) (*models.Milestone, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	milestone, err := s.milestoneRepository.UpdateMilestone(&models.Milestone{
+		ID:          milestoneId,
+		ProjectId:   projectId,
+		Name:        dto.Name,
+		Description: dto.Description,
+		DueDate:     dto.DueDate,
+		UpdatedAt:   time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrMilestoneNotFound
+		}
+		return nil, false, err
+	}
+
+	err = s.attachProgress(milestone)
+	return milestone, false, err
+}
+
+// DeleteMilestone removes the milestone and unlinks its tasks.
+func (s *milestoneService) DeleteMilestone(projectId, milestoneId, userId uuid.UUID) (bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	err = s.milestoneRepository.DeleteMilestone(projectId, milestoneId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrMilestoneNotFound
+	}
+	return false, err
+}
+
+func (s *milestoneService) attachProgress(milestones ...*models.Milestone) error {
+	ids := make([]uuid.UUID, 0, len(milestones))
+	for _, m := range milestones {
+		ids = append(ids, m.ID)
+	}
+
+	progress, err := s.milestoneRepository.GetMilestoneProgress(ids)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, m := range milestones {
+		m.Progress = progress[m.ID]
+		if m.Progress == nil {
+			m.Progress = models.NewTaskProgress(0, 0, 0, 0)
+		}
+		m.Overdue = m.IsOverdue(now)
+	}
+	return nil
+}
+
+func (s *milestoneService) checkCanEdit(projectId, userId uuid.UUID) (bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+	return !member.CanEditProject(), nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const NotificationsPerPage uint = 20
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationService interface {
+	Notify(notification *models.Notification) error
+	NotifyAssigned(task *models.Task, actorId uuid.UUID, userIds []uuid.UUID) error
+	NotifyStatusChanged(task *models.Task, from string, actorId uuid.UUID, userIds []uuid.UUID) error
+	NotifyUpdated(task *models.Task, fields []string, actorId uuid.UUID, userIds []uuid.UUID) error
+	NotifyCommented(task *models.Task, commentId, actorId uuid.UUID, userIds []uuid.UUID) error
+	NotifyInvited(projectId, userId, actorId uuid.UUID) error
+	GetNotifications(userId uuid.UUID, unreadOnly bool, page uint) ([]*models.Notification, error)
+	GetUnreadCount(userId uuid.UUID) (*models.UnreadNotifications, error)
+	MarkRead(userId, notificationId uuid.UUID) (*models.Notification, error)
+	MarkAllRead(userId uuid.UUID, dto *models.MarkNotificationsReadDTO) (*models.UnreadNotifications, error)
+}
+
+type notificationService struct {
+	notificationRepository  repository.NotificationRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	projectRepository       repository.ProjectRepository
+}
+
+func NewNotificationService(notificationRepository repository.NotificationRepository, projectMemberRepository repository.ProjectMemberRepository, projectRepository repository.ProjectRepository) NotificationService {
+	return &notificationService{
+		notificationRepository:  notificationRepository,
+		projectMemberRepository: projectMemberRepository,
+		projectRepository:       projectRepository,
+	}
+}
+
+func (s *notificationService) Notify(notification *models.Notification) error {
+	notification.ID = uuid.New()
+	_, err := s.notificationRepository.CreateNotification(notification)
+	return err
+}
+
+// NotifyAssigned tells users they were assigned to the task.
+func (s *notificationService) NotifyAssigned(task *models.Task, actorId uuid.UUID, userIds []uuid.UUID) error {
+	actor := s.actorName(task.ProjectID, actorId)
+	return s.notifyTask(task, nil, actorId, userIds, models.NotificationAssigned,
+		fmt.Sprintf("%s assigned you to %s \"%s\".", actor, task.Key, task.Title))
+}
+
+// NotifyStatusChanged tells the watchers of the task that its status changed.
+func (s *notificationService) NotifyStatusChanged(task *models.Task, from string, actorId uuid.UUID, userIds []uuid.UUID) error {
+	actor := s.actorName(task.ProjectID, actorId)
+	return s.notifyTask(task, nil, actorId, userIds, models.NotificationStatusChange,
+		fmt.Sprintf("%s moved %s \"%s\" from %s to %s.", actor, task.Key, task.Title, from, task.Status))
+}
+
+// NotifyUpdated tells the watchers of the task which of its fields changed, given by their JSON names.
+func (s *notificationService) NotifyUpdated(task *models.Task, fields []string, actorId uuid.UUID, userIds []uuid.UUID) error {
+	labels := make([]string, 0, len(fields))
+	for _, field := range fields {
+		labels = append(labels, fieldLabel(field))
+	}
+	actor := s.actorName(task.ProjectID, actorId)
+	return s.notifyTask(task, nil, actorId, userIds, models.NotificationTaskUpdated,
+		fmt.Sprintf("%s changed the %s of %s \"%s\".", actor, strings.Join(labels, ", "), task.Key, task.Title))
+}
+
+// NotifyCommented tells the watchers of the task about a new comment on it.
+func (s *notificationService) NotifyCommented(task *models.Task, commentId, actorId uuid.UUID, userIds []uuid.UUID) error {
+	actor := s.actorName(task.ProjectID, actorId)
+	return s.notifyTask(task, &commentId, actorId, userIds, models.NotificationComment,
+		fmt.Sprintf("%s commented on %s \"%s\".", actor, task.Key, task.Title))
+}
+
+// NotifyInvited tells a user they were added to a project.
+func (s *notificationService) NotifyInvited(projectId, userId, actorId uuid.UUID) error {
+	if userId == actorId {
+		return nil
+	}
+	project, err := s.projectRepository.GetProjectById(projectId, userId)
+	if err != nil {
+		return err
+	}
+	return s.Notify(&models.Notification{
+		UserId:    userId,
+		Type:      models.NotificationInvitation,
+		ProjectId: &projectId,
+		ActorId:   &actorId,
+		Message:   fmt.Sprintf("%s added you to the project \"%s\".", s.actorName(projectId, actorId), project.Name),
+	})
+}
+
+// GetNotifications returns a page of the notifications of the user, newest first.
+func (s *notificationService) GetNotifications(userId uuid.UUID, unreadOnly bool, page uint) ([]*models.Notification, error) {
+	return s.notificationRepository.GetNotifications(userId, unreadOnly, page, NotificationsPerPage)
+}
+
+func (s *notificationService) GetUnreadCount(userId uuid.UUID) (*models.UnreadNotifications, error) {
+	count, err := s.notificationRepository.GetUnreadCount(userId)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UnreadNotifications{Count: count}, nil
+}
+
+func (s *notificationService) MarkRead(userId, notificationId uuid.UUID) (*models.Notification, error) {
+	notification, err := s.notificationRepository.MarkRead(userId, notificationId, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotificationNotFound
+	}
+	return notification, err
+}
+
+// MarkAllRead marks the given notifications read, or all of them when none are given, and returns what is left unread.
+func (s *notificationService) MarkAllRead(userId uuid.UUID, dto *models.MarkNotificationsReadDTO) (*models.UnreadNotifications, error) {
+	_, err := s.notificationRepository.MarkAllRead(userId, dto.IDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.GetUnreadCount(userId)
+}
+
+// notifyTask sends a notification about the task to each user once, leaving out the user who caused it.
+func (s *notificationService) notifyTask(task *models.Task, commentId *uuid.UUID, actorId uuid.UUID, userIds []uuid.UUID, notificationType models.NotificationType, message string) error {
+	notified := map[uuid.UUID]bool{actorId: true}
+	for _, userId := range userIds {
+		if notified[userId] {
+			continue
+		}
+		notified[userId] = true
+
+		err := s.Notify(&models.Notification{
+			UserId:    userId,
+			Type:      notificationType,
+			ProjectId: &task.ProjectID,
+			TaskId:    &task.ID,
+			CommentId: commentId,
+			ActorId:   &actorId,
+			Message:   message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *notificationService) actorName(projectId, actorId uuid.UUID) string {
+	member, err := s.projectMemberRepository.GetMember(projectId, actorId)
+	if err != nil {
+		return "Someone"
+	}
+	return member.Name
+}
+
+// fieldLabel turns a JSON field name like dueDate or milestoneId into words like "due date" or "milestone".
+func fieldLabel(field string) string {
+	field = strings.TrimSuffix(field, "Id")
+	var label strings.Builder
+	for _, r := range field {
+		if unicode.IsUpper(r) {
+			label.WriteRune(' ')
+		}
+		label.WriteRune(unicode.ToLower(r))
+	}
+	return label.String()
+}
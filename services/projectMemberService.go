@@ -1,9 +1,12 @@
 package services
 
 import (
+	"database/sql"
 	"github.com/drTragger/MykroTask/models"
 	"github.com/drTragger/MykroTask/repository"
 	"github.com/google/uuid"
+	"log"
+	"time"
 )
 
 type ProjectMemberService interface {
@@ -15,10 +18,22 @@ type ProjectMemberService interface {
 
 type projectMemberService struct {
 	projectMemberRepository repository.ProjectMemberRepository
+	taskRepository          repository.TaskRepository
+	taskActivityRepository  repository.TaskActivityRepository
+	taskWatcherRepository   repository.TaskWatcherRepository
+	notificationService     NotificationService
+	db                      *sql.DB
 }
 
-func NewProjectMemberService(projectMemberRepo repository.ProjectMemberRepository) ProjectMemberService {
-	return &projectMemberService{projectMemberRepository: projectMemberRepo}
+func NewProjectMemberService(projectMemberRepo repository.ProjectMemberRepository, taskRepo repository.TaskRepository, taskActivityRepo repository.TaskActivityRepository, taskWatcherRepo repository.TaskWatcherRepository, notificationService NotificationService, db *sql.DB) ProjectMemberService {
+	return &projectMemberService{
+		projectMemberRepository: projectMemberRepo,
+		taskRepository:          taskRepo,
+		taskActivityRepository:  taskActivityRepo,
+		taskWatcherRepository:   taskWatcherRepo,
+		notificationService:     notificationService,
+		db:                      db,
+	}
 }
 
 func (s *projectMemberService) CreateMember(member *models.ProjectMember, userId uuid.UUID) (*models.ProjectMember, bool, error) {
@@ -32,8 +47,15 @@ func (s *projectMemberService) CreateMember(member *models.ProjectMember, userId
 	}
 
 	member, err = s.projectMemberRepository.CreateMember(member)
+	if err != nil {
+		return nil, false, err
+	}
 
-	return member, false, err
+	err = s.notificationService.NotifyInvited(member.ProjectId, member.UserId, userId)
+	if err != nil {
+		log.Printf("Failed to send the invitation notification to user %s: %v", member.UserId, err)
+	}
+	return member, false, nil
 }
 
 func (s *projectMemberService) GetMember(projectId, userId uuid.UUID) (*models.ProjectMember, error) {
@@ -54,5 +76,66 @@ func (s *projectMemberService) DeleteMember(projectId, memberId, userId uuid.UUI
 		return true, nil
 	}
 
-	return false, s.projectMemberRepository.DeleteMember(projectId, memberId)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = s.unassignTx(tx, projectId, memberId, userId)
+	if err != nil {
+		return false, err
+	}
+
+	// A former member no longer hears about the project's tasks.
+	err = s.taskWatcherRepository.DeleteProjectWatcherTx(tx, projectId, memberId)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.projectMemberRepository.DeleteMemberTx(tx, projectId, memberId)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	return false, err
+}
+
+// unassignTx removes a leaving member from the project's tasks, recording the change in each task's history.
+func (s *projectMemberService) unassignTx(tx *sql.Tx, projectId, memberId, actorId uuid.UUID) error {
+	tasks, err := s.taskRepository.GetTasksForUserForUpdateTx(tx, projectId, memberId)
+	if err != nil {
+		return err
+	}
+
+	for _, before := range tasks {
+		assignees := make([]uuid.UUID, 0, len(before.Assignees))
+		for _, assignee := range before.Assignees {
+			if assignee != memberId {
+				assignees = append(assignees, assignee)
+			}
+		}
+
+		after := *before
+		after.UpdatedAt = time.Now()
+		task, err := s.taskRepository.UpdateTaskTx(tx, &after)
+		if err != nil {
+			return err
+		}
+		task.Assignees, err = s.taskRepository.SetAssigneesTx(tx, task.ID, assignees)
+		if err != nil {
+			return err
+		}
+
+		err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, actorId, before, task)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -5,28 +5,40 @@ import (
 	"errors"
 	"github.com/drTragger/MykroTask/models"
 	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/storage"
+	"github.com/drTragger/MykroTask/utils"
 	"github.com/google/uuid"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const ProjectsPerPage uint = 10
 
+var ErrProjectKeyExists = errors.New("project key is already in use")
+
+// projectPatchFields are the project fields a merge patch may change.
+var projectPatchFields = []string{"name", "key", "description", "estimateUnit", "endDate"}
+
 type ProjectService interface {
 	CreateProject(project *models.Project, ownerId uuid.UUID) (*models.Project, error)
 	GetProjectsForUser(memberId uuid.UUID, page uint) ([]*models.Project, error)
 	GetProjectById(projectId, memberId uuid.UUID) (*models.Project, error)
-	UpdateProject(project *models.Project, memberId uuid.UUID) (*models.Project, bool, error)
+	UpdateProject(project *models.Project, memberId uuid.UUID, version int) (*models.Project, bool, error)
+	PatchProject(projectId, memberId uuid.UUID, version int, patch []byte) (*models.Project, bool, error)
 	DeleteProject(projectId, memberId uuid.UUID) (bool, error)
 }
 
 type projectService struct {
 	projectRepository       repository.ProjectRepository
 	projectMemberRepository repository.ProjectMemberRepository
+	attachmentRepository    repository.AttachmentRepository
+	blobStore               storage.BlobStore
 	db                      *sql.DB
 }
 
-func NewProjectService(projectRepo repository.ProjectRepository, projectMemberRepo repository.ProjectMemberRepository, db *sql.DB) ProjectService {
-	return &projectService{projectRepository: projectRepo, projectMemberRepository: projectMemberRepo, db: db}
+func NewProjectService(projectRepo repository.ProjectRepository, projectMemberRepo repository.ProjectMemberRepository, attachmentRepo repository.AttachmentRepository, blobStore storage.BlobStore, db *sql.DB) ProjectService {
+	return &projectService{projectRepository: projectRepo, projectMemberRepository: projectMemberRepo, attachmentRepository: attachmentRepo, blobStore: blobStore, db: db}
 }
 
 func (s *projectService) CreateProject(project *models.Project, ownerId uuid.UUID) (*models.Project, error) {
@@ -41,7 +53,24 @@ func (s *projectService) CreateProject(project *models.Project, ownerId uuid.UUI
 	}()
 
 	project.ID = uuid.New()
+	if project.EstimateUnit == "" {
+		project.EstimateUnit = models.EstimatePoints
+	}
+	if project.Key == "" {
+		project.Key, err = s.deriveKey(project.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
 	project, err = s.projectRepository.CreateProjectTx(tx, project)
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrProjectKeyExists
+		}
+		return nil, err
+	}
+
+	err = s.claimKeyTx(tx, project.ID, project.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -68,11 +97,21 @@ func (s *projectService) GetProjectsForUser(memberId uuid.UUID, page uint) ([]*m
 	return s.projectRepository.GetProjectsForUser(memberId, page, ProjectsPerPage)
 }
 
+// GetProjectById returns the project with the rollup of all its tasks and their estimates.
 func (s *projectService) GetProjectById(projectId, memberId uuid.UUID) (*models.Project, error) {
-	return s.projectRepository.GetProjectById(projectId, memberId)
+	project, err := s.projectRepository.GetProjectById(projectId, memberId)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Progress, err = s.projectRepository.GetProjectProgress(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
-func (s *projectService) UpdateProject(project *models.Project, memberId uuid.UUID) (*models.Project, bool, error) {
+func (s *projectService) UpdateProject(project *models.Project, memberId uuid.UUID, version int) (*models.Project, bool, error) {
 	member, err := s.projectMemberRepository.GetMember(project.ID, memberId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -85,9 +124,79 @@ func (s *projectService) UpdateProject(project *models.Project, memberId uuid.UU
 		return nil, true, nil
 	}
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Old keys stay claimed by the project, so keys of its existing tasks keep resolving.
+	if project.Key != "" {
+		err = s.claimKeyTx(tx, project.ID, project.Key)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
 	project.UpdatedAt = time.Now()
-	p, err := s.projectRepository.UpdateProject(project)
-	return p, false, err
+	project.Version = version
+	p, err := s.projectRepository.UpdateProjectTx(tx, project)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the project is gone or its version moved on, the current state tells which.
+		current, getErr := s.projectRepository.GetProjectById(project.ID, memberId)
+		if getErr != nil {
+			return nil, false, getErr
+		}
+		return current, false, ErrVersionConflict
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+	return p, false, nil
+}
+
+// PatchProject applies a JSON merge patch to the current state of the project and saves the result like UpdateProject.
+func (s *projectService) PatchProject(projectId, memberId uuid.UUID, version int, patch []byte) (*models.Project, bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, memberId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	if !member.CanEditProject() {
+		return nil, true, nil
+	}
+
+	current, err := s.projectRepository.GetProjectById(projectId, memberId)
+	if err != nil {
+		return nil, false, err
+	}
+	if current.Version != version {
+		return current, false, ErrVersionConflict
+	}
+
+	project := *current
+	fields, err := utils.ApplyMergePatch(&project, patch, projectPatchFields...)
+	if err != nil {
+		return nil, false, err
+	}
+	err = utils.ValidateStructPartial(&project, fields...)
+	if err != nil {
+		return nil, false, &ValidationError{Err: err}
+	}
+
+	return s.UpdateProject(&project, memberId, version)
 }
 
 func (s *projectService) DeleteProject(projectId, memberId uuid.UUID) (bool, error) {
@@ -103,5 +212,67 @@ func (s *projectService) DeleteProject(projectId, memberId uuid.UUID) (bool, err
 		return true, nil
 	}
 
-	return false, s.projectRepository.DeleteProject(projectId)
+	// The attachment rows go with the project, their blobs have to be removed separately.
+	storageKeys, err := s.attachmentRepository.GetStorageKeysForProject(projectId)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.projectRepository.DeleteProject(projectId)
+	if err != nil {
+		return false, err
+	}
+
+	deleteBlobs(s.blobStore, storageKeys)
+	return false, nil
+}
+
+func (s *projectService) claimKeyTx(tx *sql.Tx, projectId uuid.UUID, key string) error {
+	err := s.projectRepository.ClaimKeyTx(tx, projectId, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrProjectKeyExists
+	}
+	return err
+}
+
+// deriveKey suggests a free key from the initials of a project name, or the start of a single word name,
+// numbering it when the plain key is taken.
+func (s *projectService) deriveKey(name string) (string, error) {
+	words := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	})
+
+	var base string
+	if len(words) == 1 {
+		base = words[0]
+	} else {
+		for _, word := range words {
+			base += word[:1]
+		}
+	}
+	base = strings.TrimLeft(base, "0123456789")
+	if len(base) > 4 {
+		base = base[:4]
+	}
+	if base == "" {
+		base = "P"
+	}
+
+	for n := 1; ; n++ {
+		key := base
+		if n > 1 || len(base) < 2 {
+			key += strconv.Itoa(n)
+		}
+		if !models.ProjectKeyPattern.MatchString(key) {
+			return "", ErrProjectKeyExists
+		}
+
+		taken, err := s.projectRepository.IsKeyTaken(key)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return key, nil
+		}
+	}
 }
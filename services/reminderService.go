@@ -0,0 +1,118 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"sort"
+	"time"
+)
+
+// overdueLookback keeps reminders from going out for tasks that were overdue long before they were looked for,
+// e.g. on the first run.
+const overdueLookback = 24 * time.Hour
+
+type ReminderService interface {
+	SendDueReminders(now time.Time) (int, error)
+}
+
+type reminderService struct {
+	reminderRepository     repository.ReminderRepository
+	notificationRepository repository.NotificationRepository
+	offsets                []time.Duration
+	db                     *sql.DB
+}
+
+// NewReminderService reminds assignees of their tasks at each offset before the due date, and once more
+// when the task is overdue.
+func NewReminderService(reminderRepository repository.ReminderRepository, notificationRepository repository.NotificationRepository, offsets []time.Duration, db *sql.DB) ReminderService {
+	offsets = append([]time.Duration(nil), offsets...)
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] < offsets[j]
+	})
+	return &reminderService{
+		reminderRepository:     reminderRepository,
+		notificationRepository: notificationRepository,
+		offsets:                offsets,
+		db:                     db,
+	}
+}
+
+// SendDueReminders notifies assignees about the due dates that came within reach of an offset or passed,
+// and returns how many notifications it sent. Each reminder is sent once per due date, also when several
+// servers run it at the same time.
+func (s *reminderService) SendDueReminders(now time.Time) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	locked, err := s.reminderRepository.TryLockTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		// Another server is sending them right now.
+		return 0, tx.Rollback()
+	}
+
+	reminders, err := s.reminderRepository.ClaimRemindersTx(tx, models.ReminderOverdue, 0, now.Add(-overdueLookback), now)
+	if err != nil {
+		return 0, err
+	}
+
+	// Each offset covers the due dates up to it from the next closer one, so a task that is due soon
+	// gets the closest reminder only.
+	from := now
+	for _, offset := range s.offsets {
+		var claimed []*models.DueReminder
+		claimed, err = s.reminderRepository.ClaimRemindersTx(tx, models.ReminderDueSoon, offset, from, now.Add(offset))
+		if err != nil {
+			return 0, err
+		}
+		reminders = append(reminders, claimed...)
+		from = now.Add(offset)
+	}
+
+	for _, reminder := range reminders {
+		_, err = s.notificationRepository.CreateNotificationTx(tx, &models.Notification{
+			ID:        uuid.New(),
+			UserId:    reminder.UserId,
+			Type:      reminderNotificationType(reminder.Kind),
+			ProjectId: &reminder.ProjectId,
+			TaskId:    &reminder.TaskId,
+			Message:   reminderMessage(reminder),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return len(reminders), nil
+}
+
+func reminderNotificationType(kind models.ReminderKind) models.NotificationType {
+	if kind == models.ReminderOverdue {
+		return models.NotificationOverdue
+	}
+	return models.NotificationDueSoon
+}
+
+func reminderMessage(reminder *models.DueReminder) string {
+	dueDate := reminder.DueDate.Format(models.DateLayout)
+	if reminder.Kind == models.ReminderOverdue {
+		return fmt.Sprintf("%s \"%s\" is overdue, it was due on %s.", reminder.TaskKey, reminder.Title, dueDate)
+	}
+	return fmt.Sprintf("%s \"%s\" is due on %s.", reminder.TaskKey, reminder.Title, dueDate)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrViewNotFound = errors.New("view not found in this project")
+	ErrInvalidView  = errors.New("invalid view")
+)
+
+type SavedViewService interface {
+	CreateView(projectId, userId uuid.UUID, dto *models.SavedViewDTO) (*models.SavedView, bool, error)
+	GetViews(projectId, userId uuid.UUID) ([]*models.SavedView, bool, error)
+	GetView(projectId, viewId, userId uuid.UUID) (*models.SavedView, bool, error)
+	UpdateView(projectId, viewId, userId uuid.UUID, dto *models.SavedViewDTO) (*models.SavedView, bool, error)
+	DeleteView(projectId, viewId, userId uuid.UUID) (bool, error)
+	RunView(projectId, viewId, userId uuid.UUID) ([]*models.Task, bool, error)
+}
+
+type savedViewService struct {
+	savedViewRepository     repository.SavedViewRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	taskService             TaskService
+}
+
+func NewSavedViewService(savedViewRepository repository.SavedViewRepository, projectMemberRepository repository.ProjectMemberRepository, taskService TaskService) SavedViewService {
+	return &savedViewService{
+		savedViewRepository:     savedViewRepository,
+		projectMemberRepository: projectMemberRepository,
+		taskService:             taskService,
+	}
+}
+
+func (s *savedViewService) CreateView(projectId, userId uuid.UUID, dto *models.SavedViewDTO) (*models.SavedView, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	view := &models.SavedView{
+		ID:        uuid.New(),
+		ProjectId: projectId,
+		OwnerId:   userId,
+	}
+	err = applySavedViewDTO(view, dto)
+	if err != nil {
+		return nil, false, err
+	}
+
+	view, err = s.savedViewRepository.CreateView(view)
+	if err != nil {
+		return nil, false, err
+	}
+	return view, false, nil
+}
+
+func (s *savedViewService) GetViews(projectId, userId uuid.UUID) ([]*models.SavedView, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	views, err := s.savedViewRepository.GetViews(projectId, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	return views, false, nil
+}
+
+func (s *savedViewService) GetView(projectId, viewId, userId uuid.UUID) (*models.SavedView, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	view, err := s.getVisibleView(projectId, viewId, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	return view, false, nil
+}
+
+// UpdateView lets the owner change a view. Project admins may change shared views as well.
+func (s *savedViewService) UpdateView(projectId, viewId, userId uuid.UUID, dto *models.SavedViewDTO) (*models.SavedView, bool, error) {
+	view, forbidden, err := s.getEditableView(projectId, viewId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	err = applySavedViewDTO(view, dto)
+	if err != nil {
+		return nil, false, err
+	}
+	view.UpdatedAt = time.Now()
+
+	view, err = s.savedViewRepository.UpdateView(view)
+	if err != nil {
+		return nil, false, err
+	}
+	return view, false, nil
+}
+
+func (s *savedViewService) DeleteView(projectId, viewId, userId uuid.UUID) (bool, error) {
+	_, forbidden, err := s.getEditableView(projectId, viewId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	return false, s.savedViewRepository.DeleteView(projectId, viewId)
+}
+
+// RunView returns the project's tasks matching the view's filter, in the view's order.
+func (s *savedViewService) RunView(projectId, viewId, userId uuid.UUID) ([]*models.Task, bool, error) {
+	view, forbidden, err := s.GetView(projectId, viewId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	filter, err := parseSavedViewFilter(view.Filter)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return s.taskService.GetTasksForProject(projectId, userId, filter)
+}
+
+// getVisibleView hides other members' private views as if they did not exist.
+func (s *savedViewService) getVisibleView(projectId, viewId, userId uuid.UUID) (*models.SavedView, error) {
+	view, err := s.savedViewRepository.GetViewById(projectId, viewId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrViewNotFound
+		}
+		return nil, err
+	}
+	if view.OwnerId != userId && !view.Shared {
+		return nil, ErrViewNotFound
+	}
+	return view, nil
+}
+
+func (s *savedViewService) getEditableView(projectId, viewId, userId uuid.UUID) (*models.SavedView, bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	view, err := s.getVisibleView(projectId, viewId, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	if view.OwnerId != userId && !member.CanEditProject() {
+		return nil, true, nil
+	}
+	return view, false, nil
+}
+
+func applySavedViewDTO(view *models.SavedView, dto *models.SavedViewDTO) error {
+	filter, err := url.ParseQuery(strings.TrimPrefix(dto.Filter, "?"))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+	_, err = parseSavedViewFilter(filter.Encode())
+	if err != nil {
+		return err
+	}
+
+	columns := make([]string, 0, len(dto.Columns))
+	for _, column := range dto.Columns {
+		if !isViewColumn(column) {
+			return fmt.Errorf("%w: unknown column %q", ErrInvalidView, column)
+		}
+		columns = append(columns, column)
+	}
+
+	view.Name = dto.Name
+	view.Filter = filter.Encode()
+	view.Columns = columns
+	view.Shared = dto.Shared
+	return nil
+}
+
+func parseSavedViewFilter(filter string) (*models.TaskFilter, error) {
+	query, err := url.ParseQuery(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+	taskFilter, err := utils.ParseTaskFilter(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+	return taskFilter, nil
+}
+
+func isViewColumn(column string) bool {
+	if fieldId, ok := strings.CutPrefix(column, utils.CustomFieldParamPrefix); ok {
+		_, err := uuid.Parse(fieldId)
+		return err == nil
+	}
+	for _, viewColumn := range models.GetViewColumns() {
+		if column == viewColumn {
+			return true
+		}
+	}
+	return false
+}
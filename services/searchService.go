@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"strings"
+)
+
+const SearchResultsPerPage uint = 20
+
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+type SearchService interface {
+	Search(userId uuid.UUID, query string, page uint) ([]*models.SearchResult, error)
+}
+
+type searchService struct {
+	searchRepository repository.SearchRepository
+}
+
+func NewSearchService(searchRepository repository.SearchRepository) SearchService {
+	return &searchService{searchRepository: searchRepository}
+}
+
+// Search looks for tasks and comments in the projects the user is a member of.
+func (s *searchService) Search(userId uuid.UUID, query string, page uint) ([]*models.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	results, err := s.searchRepository.SearchTasks(userId, query, page, SearchResultsPerPage)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		result.TitleSnippet = utils.EscapeHighlight(result.TitleSnippet)
+		result.DescriptionSnippet = utils.EscapeHighlight(result.DescriptionSnippet)
+		if result.CommentSnippet != nil {
+			snippet := utils.EscapeHighlight(*result.CommentSnippet)
+			result.CommentSnippet = &snippet
+		}
+	}
+	return results, nil
+}
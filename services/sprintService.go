@@ -0,0 +1,420 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrSprintNotFound    = errors.New("sprint not found in this project")
+	ErrSprintClosed      = errors.New("sprint is closed")
+	ErrSprintNotPlanned  = errors.New("only a planned sprint can be started or deleted")
+	ErrSprintNotActive   = errors.New("only an active sprint can be closed")
+	ErrSprintActive      = errors.New("project already has an active sprint")
+	ErrInvalidNextSprint = errors.New("next sprint must be another open sprint of this project")
+)
+
+type SprintService interface {
+	CreateSprint(projectId, userId uuid.UUID, dto *models.SprintDTO) (*models.Sprint, bool, error)
+	GetSprints(projectId, userId uuid.UUID) ([]*models.Sprint, bool, error)
+	GetSprint(projectId, sprintId, userId uuid.UUID) (*models.Sprint, bool, error)
+	UpdateSprint(projectId, sprintId, userId uuid.UUID, dto *models.SprintDTO) (*models.Sprint, bool, error)
+	DeleteSprint(projectId, sprintId, userId uuid.UUID) (bool, error)
+	StartSprint(projectId, sprintId, userId uuid.UUID) (*models.Sprint, bool, error)
+	CloseSprint(projectId, sprintId, userId uuid.UUID, dto *models.CloseSprintDTO) (*models.Sprint, bool, error)
+	GetVelocity(projectId, userId uuid.UUID) (*models.Velocity, bool, error)
+}
+
+type sprintService struct {
+	sprintRepository        repository.SprintRepository
+	taskRepository          repository.TaskRepository
+	projectRepository       repository.ProjectRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	taskActivityRepository  repository.TaskActivityRepository
+	db                      *sql.DB
+}
+
+func NewSprintService(sprintRepository repository.SprintRepository, taskRepository repository.TaskRepository, projectRepository repository.ProjectRepository, projectMemberRepository repository.ProjectMemberRepository, taskActivityRepository repository.TaskActivityRepository, db *sql.DB) SprintService {
+	return &sprintService{
+		sprintRepository:        sprintRepository,
+		taskRepository:          taskRepository,
+		projectRepository:       projectRepository,
+		projectMemberRepository: projectMemberRepository,
+		taskActivityRepository:  taskActivityRepository,
+		db:                      db,
+	}
+}
+
+func (s *sprintService) CreateSprint(projectId, userId uuid.UUID, dto *models.SprintDTO) (*models.Sprint, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	sprint, err := s.sprintRepository.CreateSprint(&models.Sprint{
+		ID:        uuid.New(),
+		ProjectId: projectId,
+		Name:      dto.Name,
+		Goal:      dto.Goal,
+		StartDate: dto.StartDate,
+		EndDate:   dto.EndDate,
+		State:     models.SprintPlanned,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(sprint)
+	return sprint, false, err
+}
+
+func (s *sprintService) GetSprints(projectId, userId uuid.UUID) ([]*models.Sprint, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	sprints, err := s.sprintRepository.GetSprints(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(sprints...)
+	return sprints, false, err
+}
+
+func (s *sprintService) GetSprint(projectId, sprintId, userId uuid.UUID) (*models.Sprint, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	sprint, err := s.getSprint(projectId, sprintId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(sprint)
+	return sprint, false, err
+}
+
+// UpdateSprint changes the plan of a sprint, a closed sprint stays as it was.
+func (s *sprintService) UpdateSprint(projectId, sprintId, userId uuid.UUID, dto *models.SprintDTO) (*models.Sprint, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	current, err := s.getSprint(projectId, sprintId)
+	if err != nil {
+		return nil, false, err
+	}
+	if current.IsClosed() {
+		return nil, false, ErrSprintClosed
+	}
+
+	sprint, err := s.sprintRepository.UpdateSprint(&models.Sprint{
+		ID:        sprintId,
+		ProjectId: projectId,
+		Name:      dto.Name,
+		Goal:      dto.Goal,
+		StartDate: dto.StartDate,
+		EndDate:   dto.EndDate,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrSprintClosed
+		}
+		return nil, false, err
+	}
+
+	err = s.attachProgress(sprint)
+	return sprint, false, err
+}
+
+// DeleteSprint removes a planned sprint and moves its tasks back to the backlog.
+func (s *sprintService) DeleteSprint(projectId, sprintId, userId uuid.UUID) (bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	sprint, err := s.getSprint(projectId, sprintId)
+	if err != nil {
+		return false, err
+	}
+	if sprint.State != models.SprintPlanned {
+		return false, ErrSprintNotPlanned
+	}
+
+	err = s.sprintRepository.DeleteSprint(projectId, sprintId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrSprintNotFound
+	}
+	return false, err
+}
+
+// StartSprint activates a planned sprint and records how many tasks and how much estimate it was committed with.
+func (s *sprintService) StartSprint(projectId, sprintId, userId uuid.UUID) (*models.Sprint, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sprint, err := s.getSprintForUpdateTx(tx, projectId, sprintId)
+	if err != nil {
+		return nil, false, err
+	}
+	if sprint.State != models.SprintPlanned {
+		err = ErrSprintNotPlanned
+		return nil, false, err
+	}
+
+	tasks, err := s.taskRepository.GetSprintTasksForUpdateTx(tx, sprintId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	committed := len(tasks)
+	var committedEstimate float64
+	for _, task := range tasks {
+		committedEstimate += task.EstimateValue()
+	}
+	sprint.State = models.SprintActive
+	sprint.CommittedTasks = &committed
+	sprint.CommittedEstimate = &committedEstimate
+	sprint.StartedAt = &now
+	sprint.UpdatedAt = now
+	sprint, err = s.sprintRepository.UpdateSprintStateTx(tx, sprint)
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrSprintActive
+		}
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(sprint)
+	return sprint, false, err
+}
+
+// CloseSprint closes the active sprint. Its unfinished tasks move to the next sprint or the backlog,
+// and the counts and estimates of completed and carried over tasks are kept with the sprint.
+func (s *sprintService) CloseSprint(projectId, sprintId, userId uuid.UUID, dto *models.CloseSprintDTO) (*models.Sprint, bool, error) {
+	forbidden, err := s.checkCanEdit(projectId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+	if dto.NextSprintId != nil && *dto.NextSprintId == sprintId {
+		return nil, false, ErrInvalidNextSprint
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sprint, err := s.getSprintForUpdateTx(tx, projectId, sprintId)
+	if err != nil {
+		return nil, false, err
+	}
+	if sprint.State != models.SprintActive {
+		err = ErrSprintNotActive
+		return nil, false, err
+	}
+
+	if dto.NextSprintId != nil {
+		var next *models.Sprint
+		next, err = s.sprintRepository.GetSprintByIdForUpdateTx(tx, projectId, *dto.NextSprintId)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && next.IsClosed()) {
+			err = ErrInvalidNextSprint
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	tasks, err := s.taskRepository.GetSprintTasksForUpdateTx(tx, sprintId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	completed, carriedOver := 0, 0
+	var completedEstimate, carriedOverEstimate float64
+	for _, before := range tasks {
+		if before.IsDone() {
+			completed++
+			completedEstimate += before.EstimateValue()
+			continue
+		}
+
+		after := *before
+		after.SprintID = dto.NextSprintId
+		after.UpdatedAt = now
+		var task *models.Task
+		task, err = s.taskRepository.UpdateTaskTx(tx, &after)
+		if err != nil {
+			return nil, false, err
+		}
+		err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, userId, before, task)
+		if err != nil {
+			return nil, false, err
+		}
+		carriedOver++
+		carriedOverEstimate += before.EstimateValue()
+	}
+
+	sprint.State = models.SprintClosed
+	sprint.CompletedTasks = &completed
+	sprint.CarriedOverTasks = &carriedOver
+	sprint.CompletedEstimate = &completedEstimate
+	sprint.CarriedOverEstimate = &carriedOverEstimate
+	sprint.ClosedAt = &now
+	sprint.UpdatedAt = now
+	sprint, err = s.sprintRepository.UpdateSprintStateTx(tx, sprint)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(sprint)
+	return sprint, false, err
+}
+
+// GetVelocity reports what each closed sprint of the project committed to and completed.
+func (s *sprintService) GetVelocity(projectId, userId uuid.UUID) (*models.Velocity, bool, error) {
+	project, err := s.projectRepository.GetProjectById(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	sprints, err := s.sprintRepository.GetSprints(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	velocity := &models.Velocity{Unit: project.EstimateUnit, Sprints: []*models.SprintVelocity{}}
+	for _, sprint := range sprints {
+		if !sprint.IsClosed() {
+			continue
+		}
+		velocity.Sprints = append(velocity.Sprints, &models.SprintVelocity{
+			SprintId:          sprint.ID,
+			Name:              sprint.Name,
+			StartDate:         sprint.StartDate,
+			EndDate:           sprint.EndDate,
+			CommittedTasks:    intValue(sprint.CommittedTasks),
+			CompletedTasks:    intValue(sprint.CompletedTasks),
+			CommittedEstimate: floatValue(sprint.CommittedEstimate),
+			CompletedEstimate: floatValue(sprint.CompletedEstimate),
+		})
+	}
+
+	latest := velocity.Sprints
+	if len(latest) > models.VelocitySprints {
+		latest = latest[len(latest)-models.VelocitySprints:]
+	}
+	for _, sprint := range latest {
+		velocity.Average += sprint.CompletedEstimate / float64(len(latest))
+	}
+	return velocity, false, nil
+}
+
+func (s *sprintService) getSprint(projectId, sprintId uuid.UUID) (*models.Sprint, error) {
+	sprint, err := s.sprintRepository.GetSprintById(projectId, sprintId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSprintNotFound
+	}
+	return sprint, err
+}
+
+func (s *sprintService) getSprintForUpdateTx(tx *sql.Tx, projectId, sprintId uuid.UUID) (*models.Sprint, error) {
+	sprint, err := s.sprintRepository.GetSprintByIdForUpdateTx(tx, projectId, sprintId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSprintNotFound
+	}
+	return sprint, err
+}
+
+func (s *sprintService) attachProgress(sprints ...*models.Sprint) error {
+	ids := make([]uuid.UUID, 0, len(sprints))
+	for _, sprint := range sprints {
+		ids = append(ids, sprint.ID)
+	}
+
+	progress, err := s.sprintRepository.GetSprintProgress(ids)
+	if err != nil {
+		return err
+	}
+	for _, sprint := range sprints {
+		sprint.Progress = progress[sprint.ID]
+		if sprint.Progress == nil {
+			sprint.Progress = models.NewTaskProgress(0, 0, 0, 0)
+		}
+	}
+	return nil
+}
+
+func (s *sprintService) checkCanEdit(projectId, userId uuid.UUID) (bool, error) {
+	member, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+	return !member.CanEditProject(), nil
+}
+
+func intValue(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+func floatValue(n *float64) float64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
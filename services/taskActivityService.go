@@ -0,0 +1,80 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+)
+
+const ActivitiesPerPage uint = 50
+
+type TaskActivityService interface {
+	GetTaskActivities(projectId, taskId, userId uuid.UUID) ([]*models.TaskActivity, bool, error)
+	GetProjectActivities(projectId, userId uuid.UUID, page uint) ([]*models.TaskActivity, bool, error)
+}
+
+type taskActivityService struct {
+	taskActivityRepository  repository.TaskActivityRepository
+	projectMemberRepository repository.ProjectMemberRepository
+}
+
+func NewTaskActivityService(taskActivityRepository repository.TaskActivityRepository, projectMemberRepository repository.ProjectMemberRepository) TaskActivityService {
+	return &taskActivityService{
+		taskActivityRepository:  taskActivityRepository,
+		projectMemberRepository: projectMemberRepository,
+	}
+}
+
+// GetTaskActivities returns the timeline of a task, including entries of a task that has since been deleted.
+func (s *taskActivityService) GetTaskActivities(projectId, taskId, userId uuid.UUID) ([]*models.TaskActivity, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	activities, err := s.taskActivityRepository.GetTaskActivities(projectId, taskId)
+	return activities, false, err
+}
+
+func (s *taskActivityService) GetProjectActivities(projectId, userId uuid.UUID, page uint) ([]*models.TaskActivity, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	activities, err := s.taskActivityRepository.GetProjectActivities(projectId, page, ActivitiesPerPage)
+	return activities, false, err
+}
+
+// recordTaskActivityTx writes the field-level diff between two versions of a task to the activity history.
+func recordTaskActivityTx(taskActivityRepository repository.TaskActivityRepository, tx *sql.Tx, action models.ActivityAction, actorId uuid.UUID, before, after *models.Task) error {
+	changes, err := models.DiffTasks(before, after)
+	if err != nil {
+		return err
+	}
+	if action == models.ActivityUpdated && len(changes) == 0 {
+		return nil
+	}
+
+	task := after
+	if task == nil {
+		task = before
+	}
+	_, err = taskActivityRepository.CreateActivityTx(tx, &models.TaskActivity{
+		ID:        uuid.New(),
+		TaskId:    task.ID,
+		ProjectId: task.ProjectID,
+		ActorId:   &actorId,
+		Action:    action,
+		Changes:   changes,
+	})
+	return err
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSelfDependency   = errors.New("task cannot depend on itself")
+	ErrDependencyCycle  = errors.New("dependency would create a cycle")
+	ErrDependencyExists = errors.New("dependency already exists")
+)
+
+type TaskDependencyService interface {
+	CreateDependency(projectId, taskId, userId uuid.UUID, dto *models.CreateTaskDependencyDTO) (*models.TaskDependency, bool, error)
+	DeleteDependency(projectId, taskId, otherTaskId, userId uuid.UUID) (bool, error)
+}
+
+type taskDependencyService struct {
+	taskDependencyRepository repository.TaskDependencyRepository
+	taskRepository           repository.TaskRepository
+	projectMemberRepository  repository.ProjectMemberRepository
+	db                       *sql.DB
+}
+
+func NewTaskDependencyService(taskDependencyRepository repository.TaskDependencyRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository, db *sql.DB) TaskDependencyService {
+	return &taskDependencyService{
+		taskDependencyRepository: taskDependencyRepository,
+		taskRepository:           taskRepository,
+		projectMemberRepository:  projectMemberRepository,
+		db:                       db,
+	}
+}
+
+func (s *taskDependencyService) CreateDependency(projectId, taskId, userId uuid.UUID, dto *models.CreateTaskDependencyDTO) (*models.TaskDependency, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	if taskId == dto.TaskId {
+		return nil, false, ErrSelfDependency
+	}
+
+	for _, id := range []uuid.UUID{taskId, dto.TaskId} {
+		_, err = s.taskRepository.GetTaskById(projectId, id)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	dependency := &models.TaskDependency{
+		BlockerId: taskId,
+		BlockedId: dto.TaskId,
+		ProjectId: projectId,
+		CreatedBy: userId,
+	}
+	if dto.Type == models.DependencyBlockedBy {
+		dependency.BlockerId, dependency.BlockedId = dto.TaskId, taskId
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = s.taskDependencyRepository.LockDependenciesTx(tx, projectId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The new edge closes a cycle if the blocked task already blocks the blocker.
+	cycle, err := s.taskDependencyRepository.IsBlockingTx(tx, dependency.BlockedId, dependency.BlockerId)
+	if err != nil {
+		return nil, false, err
+	}
+	if cycle {
+		err = ErrDependencyCycle
+		return nil, false, err
+	}
+
+	dependency, err = s.taskDependencyRepository.CreateDependencyTx(tx, dependency)
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrDependencyExists
+		}
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+	return dependency, false, nil
+}
+
+func (s *taskDependencyService) DeleteDependency(projectId, taskId, otherTaskId, userId uuid.UUID) (bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return false, s.taskDependencyRepository.DeleteDependency(projectId, taskId, otherTaskId)
+}
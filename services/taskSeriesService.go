@@ -0,0 +1,313 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/utils"
+	"github.com/google/uuid"
+	"log"
+	"time"
+)
+
+var (
+	ErrSeriesNotFound     = errors.New("task series not found in this project")
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrTaskInSeries       = errors.New("task already repeats in a series")
+	ErrDoneTaskRecurrence = errors.New("a done task cannot start a series")
+)
+
+type TaskSeriesService interface {
+	CreateSeries(projectId, taskId, userId uuid.UUID, dto *models.RecurrenceDTO) (*models.TaskSeries, bool, error)
+	GetSeries(projectId, seriesId, userId uuid.UUID) (*models.TaskSeries, bool, error)
+	UpdateSeries(projectId, seriesId, userId uuid.UUID, dto *models.TaskSeriesDTO) (*models.TaskSeries, bool, error)
+	DeleteSeries(projectId, seriesId, userId uuid.UUID) (bool, error)
+}
+
+type taskSeriesService struct {
+	taskSeriesRepository    repository.TaskSeriesRepository
+	taskRepository          repository.TaskRepository
+	projectMemberRepository repository.ProjectMemberRepository
+	taskActivityRepository  repository.TaskActivityRepository
+	taskWatcherRepository   repository.TaskWatcherRepository
+	notificationService     NotificationService
+	db                      *sql.DB
+}
+
+func NewTaskSeriesService(taskSeriesRepository repository.TaskSeriesRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository, taskActivityRepository repository.TaskActivityRepository, taskWatcherRepository repository.TaskWatcherRepository, notificationService NotificationService, db *sql.DB) TaskSeriesService {
+	return &taskSeriesService{
+		taskSeriesRepository:    taskSeriesRepository,
+		taskRepository:          taskRepository,
+		projectMemberRepository: projectMemberRepository,
+		taskActivityRepository:  taskActivityRepository,
+		taskWatcherRepository:   taskWatcherRepository,
+		notificationService:     notificationService,
+		db:                      db,
+	}
+}
+
+// CreateSeries makes the task the first instance of a series repeating by the rule. The series starts
+// at the due date of the task, or now when it has none, and copies the task as the template of its instances.
+func (s *taskSeriesService) CreateSeries(projectId, taskId, userId uuid.UUID, dto *models.RecurrenceDTO) (*models.TaskSeries, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	rule, err := parseRecurrence(dto.Rule)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	before, err := s.taskRepository.GetTaskByIdForUpdateTx(tx, projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+	if before.SeriesID != nil {
+		err = ErrTaskInSeries
+		return nil, false, err
+	}
+	if before.IsDone() {
+		err = ErrDoneTaskRecurrence
+		return nil, false, err
+	}
+
+	startsAt := time.Now().UTC().Truncate(time.Minute)
+	if before.DueDate != nil {
+		startsAt = before.DueDate.UTC()
+	}
+	series := &models.TaskSeries{
+		ID:               uuid.New(),
+		ProjectId:        projectId,
+		Rule:             rule.String(),
+		StartsAt:         startsAt,
+		LastOccurrenceAt: startsAt,
+		Occurrences:      1,
+		Title:            before.Title,
+		Description:      before.Description,
+		Status:           before.Status,
+		Priority:         before.Priority,
+		Estimate:         before.Estimate,
+		Assignees:        before.Assignees,
+		CreatedBy:        userId,
+	}
+	if next, ok := nextOccurrence(rule, series, startsAt, 1); ok {
+		series.NextAt = &next
+	}
+	series, err = s.taskSeriesRepository.CreateSeriesTx(tx, series)
+	if err != nil {
+		return nil, false, err
+	}
+
+	after := *before
+	after.SeriesID = &series.ID
+	after.OccurrenceAt = &startsAt
+	after.UpdatedAt = time.Now()
+	task, err := s.taskRepository.UpdateTaskTx(tx, &after)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, userId, before, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	series.Instances = []*models.Task{task}
+	return series, false, nil
+}
+
+// GetSeries returns the series with all of its instances.
+func (s *taskSeriesService) GetSeries(projectId, seriesId, userId uuid.UUID) (*models.TaskSeries, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	series, err := s.taskSeriesRepository.GetSeriesById(projectId, seriesId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrSeriesNotFound
+		}
+		return nil, false, err
+	}
+
+	series.Instances, err = s.taskSeriesRepository.GetInstances(seriesId)
+	if err != nil {
+		return nil, false, err
+	}
+	return series, false, nil
+}
+
+// UpdateSeries changes the template of the series and applies it to the instances that are not done yet.
+// A new rule starts from the latest occurrence, which counts as its first one.
+func (s *taskSeriesService) UpdateSeries(projectId, seriesId, userId uuid.UUID, dto *models.TaskSeriesDTO) (*models.TaskSeries, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	rule, err := parseRecurrence(dto.Rule)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, assignee := range dto.Assignees {
+		_, err = s.projectMemberRepository.GetMember(projectId, assignee)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, false, ErrAssigneeNotMember
+			}
+			return nil, false, err
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	series, err := s.taskSeriesRepository.GetSeriesByIdForUpdateTx(tx, projectId, seriesId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrSeriesNotFound
+		}
+		return nil, false, err
+	}
+
+	if rule.String() != series.Rule {
+		series.Rule = rule.String()
+		series.StartsAt = series.LastOccurrenceAt
+		series.Occurrences = 1
+		series.NextAt = nil
+		if next, ok := nextOccurrence(rule, series, series.StartsAt, 1); ok {
+			series.NextAt = &next
+		}
+	}
+	series.Title = dto.Title
+	series.Description = dto.Description
+	series.Status = dto.Status
+	series.Priority = dto.Priority
+	series.Estimate = dto.Estimate
+	series.Assignees = dto.Assignees
+	series.UpdatedAt = time.Now()
+	series, err = s.taskSeriesRepository.UpdateSeriesTx(tx, series)
+	if err != nil {
+		return nil, false, err
+	}
+
+	instances, err := s.taskSeriesRepository.GetOpenInstancesForUpdateTx(tx, seriesId)
+	if err != nil {
+		return nil, false, err
+	}
+	assigned := make(map[*models.Task][]uuid.UUID, len(instances))
+	for _, instance := range instances {
+		before := *instance
+		instance.Title = series.Title
+		instance.Description = series.Description
+		instance.Priority = series.Priority
+		instance.Estimate = series.Estimate
+		instance.UpdatedAt = series.UpdatedAt
+		instance, err = s.taskRepository.UpdateTaskTx(tx, instance)
+		if err != nil {
+			return nil, false, err
+		}
+		instance.Assignees, err = s.taskRepository.SetAssigneesTx(tx, instance.ID, series.Assignees)
+		if err != nil {
+			return nil, false, err
+		}
+		assigned[instance] = addedAssignees(&before, instance)
+		err = s.taskWatcherRepository.AddWatchersTx(tx, instance.ID, assigned[instance])
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, userId, &before, instance)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for instance, userIds := range assigned {
+		if len(userIds) == 0 {
+			continue
+		}
+		err = s.notificationService.NotifyAssigned(instance, userId, userIds)
+		if err != nil {
+			log.Printf("Failed to send assignment notifications for task %s: %v", instance.ID, err)
+		}
+	}
+
+	series.Instances, err = s.taskSeriesRepository.GetInstances(seriesId)
+	if err != nil {
+		return nil, false, err
+	}
+	return series, false, nil
+}
+
+// DeleteSeries stops the recurrence. The instances created so far stay as ordinary tasks.
+func (s *taskSeriesService) DeleteSeries(projectId, seriesId, userId uuid.UUID) (bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	err = s.taskSeriesRepository.DeleteSeries(projectId, seriesId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrSeriesNotFound
+	}
+	return false, err
+}
+
+// parseRecurrence reads the rule of a series. The rule is stored in its canonical form, which has to read
+// back the same way, or the scheduler could not create the instances later on.
+func parseRecurrence(value string) (*utils.RRule, error) {
+	rule, err := utils.ParseRRule(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	stored, err := utils.ParseRRule(rule.String())
+	if err != nil || stored.String() != rule.String() {
+		return nil, fmt.Errorf("%w: %s cannot be stored", ErrInvalidRecurrence, value)
+	}
+	return rule, nil
+}
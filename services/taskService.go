@@ -3,28 +3,91 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/drTragger/MykroTask/models"
 	"github.com/drTragger/MykroTask/repository"
+	"github.com/drTragger/MykroTask/storage"
+	"github.com/drTragger/MykroTask/utils"
 	"github.com/google/uuid"
+	"log"
+	"sort"
+	"strings"
 	"time"
 )
 
+var (
+	ErrParentNotFound    = errors.New("parent task not found in this project")
+	ErrTaskCycle         = errors.New("task cannot be a descendant of itself")
+	ErrMaxDepthExceeded  = errors.New("task hierarchy is too deep")
+	ErrAssigneeNotMember = errors.New("assignee is not a member of this project")
+	ErrInvalidTaskFilter = errors.New("invalid task filter")
+	ErrInvalidMove       = errors.New("neighbours must be other tasks of the target column in board order")
+)
+
+// taskPatchFields are the task fields a merge patch may change.
+var taskPatchFields = []string{"title", "description", "status", "priority", "assignees", "customFields", "dueDate", "parentId", "milestoneId", "sprintId", "estimate"}
+
+// UnfinishedBlockersError is returned when a task is moved to a done status while it is still blocked.
+type UnfinishedBlockersError struct {
+	Blockers []*models.TaskLink
+}
+
+func (e *UnfinishedBlockersError) Error() string {
+	return "task is blocked by unfinished tasks"
+}
+
 type TaskService interface {
 	CreateTask(task *models.Task) (*models.Task, bool, error)
-	GetTasksForUser(projectId, memberId, userId uuid.UUID) ([]*models.Task, bool, error)
+	GetTasksForUser(projectId, memberId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, bool, error)
 	GetTaskById(projectId, taskId, memberId uuid.UUID) (*models.Task, bool, error)
+	GetTaskByKey(key string, memberId uuid.UUID) (*models.Task, bool, error)
 	DeleteTask(projectId, taskId, memberId uuid.UUID) (bool, error)
-	GetTasksForProject(projectId, userId uuid.UUID) ([]*models.Task, bool, error)
-	UpdateTask(projectId, taskId, userId uuid.UUID, task *models.Task) (*models.Task, bool, error)
+	GetTasksForProject(projectId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, bool, error)
+	UpdateTask(projectId, taskId, userId uuid.UUID, version int, task *models.Task) (*models.Task, bool, error)
+	PatchTask(projectId, taskId, userId uuid.UUID, version int, patch []byte) (*models.Task, bool, error)
+	GetChildren(projectId, taskId, userId uuid.UUID) ([]*models.Task, bool, error)
+	GetTaskTree(projectId, taskId, userId uuid.UUID) (*models.Task, bool, error)
+	MoveTask(projectId, taskId, userId uuid.UUID, dto *models.MoveTaskDTO) (*models.Task, bool, error)
+	GetBoard(projectId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.BoardColumn, bool, error)
+	CreateDueInstances(now time.Time) (int, error)
 }
 
 type taskService struct {
-	taskRepository          repository.TaskRepository
-	projectMemberRepository repository.ProjectMemberRepository
+	taskRepository           repository.TaskRepository
+	projectMemberRepository  repository.ProjectMemberRepository
+	taskDependencyRepository repository.TaskDependencyRepository
+	mentionRepository        repository.MentionRepository
+	taskActivityRepository   repository.TaskActivityRepository
+	customFieldRepository    repository.CustomFieldRepository
+	milestoneRepository      repository.MilestoneRepository
+	sprintRepository         repository.SprintRepository
+	taskSeriesRepository     repository.TaskSeriesRepository
+	taskWatcherRepository    repository.TaskWatcherRepository
+	attachmentRepository     repository.AttachmentRepository
+	blobStore                storage.BlobStore
+	mentionService           MentionService
+	notificationService      NotificationService
+	db                       *sql.DB
 }
 
-func NewTaskService(taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository) TaskService {
-	return &taskService{taskRepository: taskRepository, projectMemberRepository: projectMemberRepository}
+func NewTaskService(taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository, taskDependencyRepository repository.TaskDependencyRepository, mentionRepository repository.MentionRepository, taskActivityRepository repository.TaskActivityRepository, customFieldRepository repository.CustomFieldRepository, milestoneRepository repository.MilestoneRepository, sprintRepository repository.SprintRepository, taskSeriesRepository repository.TaskSeriesRepository, taskWatcherRepository repository.TaskWatcherRepository, attachmentRepository repository.AttachmentRepository, blobStore storage.BlobStore, mentionService MentionService, notificationService NotificationService, db *sql.DB) TaskService {
+	return &taskService{
+		taskRepository:           taskRepository,
+		projectMemberRepository:  projectMemberRepository,
+		taskDependencyRepository: taskDependencyRepository,
+		mentionRepository:        mentionRepository,
+		taskActivityRepository:   taskActivityRepository,
+		customFieldRepository:    customFieldRepository,
+		milestoneRepository:      milestoneRepository,
+		sprintRepository:         sprintRepository,
+		taskSeriesRepository:     taskSeriesRepository,
+		taskWatcherRepository:    taskWatcherRepository,
+		attachmentRepository:     attachmentRepository,
+		blobStore:                blobStore,
+		mentionService:           mentionService,
+		notificationService:      notificationService,
+		db:                       db,
+	}
 }
 
 func (s *taskService) CreateTask(task *models.Task) (*models.Task, bool, error) {
@@ -36,20 +99,91 @@ func (s *taskService) CreateTask(task *models.Task) (*models.Task, bool, error)
 		return nil, false, err
 	}
 
-	_, err = s.projectMemberRepository.GetMember(task.ProjectID, task.Assignee)
+	task.ID = uuid.New()
+	// A task joins a series through its recurrence, not by being created with one.
+	task.SeriesID, task.OccurrenceAt = nil, nil
+	err = s.validateParent(task)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, true, nil
+		return nil, false, err
+	}
+
+	err = s.validateAssignees(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.validateCustomFields(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.validateMilestone(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.validateSprint(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
 		}
+	}()
+
+	task.Rank, err = s.placeTaskTx(tx, task, task.Status, &models.MoveTaskDTO{})
+	if err != nil {
 		return nil, false, err
 	}
 
-	task.ID = uuid.New()
-	task, err = s.taskRepository.CreateTask(task)
-	return task, false, err
+	assignees, customFields := task.Assignees, task.CustomFields
+	task, err = s.taskRepository.CreateTaskTx(tx, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	task.Assignees, err = s.taskRepository.SetAssigneesTx(tx, task.ID, assignees)
+	if err != nil {
+		return nil, false, err
+	}
+	// The creator and the assignees watch the task.
+	err = s.taskWatcherRepository.AddWatchersTx(tx, task.ID, append([]uuid.UUID{task.CreatedBy}, task.Assignees...))
+	if err != nil {
+		return nil, false, err
+	}
+	task.CustomFields, err = s.taskRepository.SetCustomFieldValuesTx(tx, task.ID, customFields)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityCreated, task.CreatedBy, nil, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var mentioned []uuid.UUID
+	task.Mentions, task.UnresolvedMentions, mentioned, err = s.mentionService.SyncMentionsTx(tx, task, nil, task.CreatedBy, task.Description)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mentionService.NotifyMentioned(task, nil, task.CreatedBy, mentioned)
+	s.notifyChanges(nil, task, task.CreatedBy)
+	return task, false, nil
 }
 
-func (s *taskService) GetTasksForUser(projectId, memberId, userId uuid.UUID) ([]*models.Task, bool, error) {
+func (s *taskService) GetTasksForUser(projectId, memberId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, bool, error) {
 	_, err := s.projectMemberRepository.GetMember(projectId, memberId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -58,8 +192,17 @@ func (s *taskService) GetTasksForUser(projectId, memberId, userId uuid.UUID) ([]
 		return nil, false, err
 	}
 
-	tasks, err := s.taskRepository.GetTasksForUser(projectId, userId)
+	err = s.resolveFilter(projectId, filter)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tasks, err := s.taskRepository.GetTasksForUser(projectId, userId, filter)
+	if err != nil {
+		return nil, false, err
+	}
 
+	err = s.attachProgress(tasks...)
 	return tasks, false, err
 }
 
@@ -77,9 +220,49 @@ func (s *taskService) GetTaskById(projectId, taskId, memberId uuid.UUID) (*model
 		return nil, false, err
 	}
 
+	err = s.attachProgress(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	task.BlockedBy, err = s.taskDependencyRepository.GetBlockers(task.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	task.Blocking, err = s.taskDependencyRepository.GetBlocking(task.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	task.Mentions, err = s.mentionRepository.GetMentions(task.ID, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
 	return task, false, nil
 }
 
+// GetTaskByKey finds a task by a key like WEB-123 and loads it like GetTaskById. Callers outside the project
+// get sql.ErrNoRows as for a missing task, so keys cannot be probed.
+func (s *taskService) GetTaskByKey(key string, memberId uuid.UUID) (*models.Task, bool, error) {
+	projectKey, number, ok := models.ParseTaskKey(key)
+	if !ok {
+		return nil, false, sql.ErrNoRows
+	}
+
+	task, err := s.taskRepository.GetTaskByKey(projectKey, number)
+	if err != nil {
+		return nil, false, err
+	}
+
+	task, forbidden, err := s.GetTaskById(task.ProjectID, task.ID, memberId)
+	if forbidden {
+		return nil, false, sql.ErrNoRows
+	}
+	return task, false, err
+}
+
 func (s *taskService) DeleteTask(projectId, taskId, memberId uuid.UUID) (bool, error) {
 	member, err := s.projectMemberRepository.GetMember(projectId, memberId)
 	if err != nil {
@@ -93,14 +276,47 @@ func (s *taskService) DeleteTask(projectId, taskId, memberId uuid.UUID) (bool, e
 		return true, nil
 	}
 
-	err = s.taskRepository.DeleteTask(projectId, taskId)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	task, err := s.taskRepository.GetTaskByIdForUpdateTx(tx, projectId, taskId)
+	if err != nil {
+		return false, err
+	}
+
+	// The attachment rows go with the task, their blobs have to be removed separately.
+	storageKeys, err := s.attachmentRepository.GetStorageKeysForTaskTx(tx, taskId)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.taskRepository.DeleteTaskTx(tx, projectId, taskId)
+	if err != nil {
+		return false, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityDeleted, memberId, task, nil)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return false, err
 	}
+
+	deleteBlobs(s.blobStore, storageKeys)
 	return false, nil
 }
 
-func (s *taskService) GetTasksForProject(projectId, userId uuid.UUID) ([]*models.Task, bool, error) {
+func (s *taskService) GetTasksForProject(projectId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.Task, bool, error) {
 	_, err := s.projectMemberRepository.GetMember(projectId, userId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -109,14 +325,24 @@ func (s *taskService) GetTasksForProject(projectId, userId uuid.UUID) ([]*models
 		return nil, false, err
 	}
 
-	tasks, err := s.taskRepository.GetTasksForProject(projectId, userId)
+	err = s.resolveFilter(projectId, filter)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tasks, err := s.taskRepository.GetTasksForProject(projectId, filter)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(tasks...)
 	if err != nil {
 		return nil, false, err
 	}
 	return tasks, false, nil
 }
 
-func (s *taskService) UpdateTask(projectId, taskId, userId uuid.UUID, task *models.Task) (*models.Task, bool, error) {
+func (s *taskService) UpdateTask(projectId, taskId, userId uuid.UUID, version int, task *models.Task) (*models.Task, bool, error) {
 	_, err := s.projectMemberRepository.GetMember(projectId, userId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -128,6 +354,865 @@ func (s *taskService) UpdateTask(projectId, taskId, userId uuid.UUID, task *mode
 	task.ID = taskId
 	task.ProjectID = projectId
 	task.UpdatedAt = time.Now()
-	task, err = s.taskRepository.UpdateTask(task)
+	err = s.validateParent(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.validateAssignees(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.validateCustomFields(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.validateMilestone(task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	before, err := s.taskRepository.GetTaskByIdForUpdateTx(tx, projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+	if before.Version != version {
+		err = ErrVersionConflict
+		return before, false, err
+	}
+
+	// Only closing a task is held up by open blockers, so edits of a done task still go through.
+	if !before.IsDone() && task.IsDone() {
+		err = s.checkBlockers(task.ID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Tasks stay in a closed sprint, but no task can be moved into one.
+	if !sameId(task.SprintID, before.SprintID) {
+		err = s.validateSprint(task)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	task.SeriesID, task.OccurrenceAt = before.SeriesID, before.OccurrenceAt
+
+	// A task moved to another status goes to the end of that column.
+	task.Rank = before.Rank
+	if task.Status != before.Status {
+		task.Rank, err = s.placeTaskTx(tx, before, task.Status, &models.MoveTaskDTO{})
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	task.Version = version
+	assignees, customFields := task.Assignees, task.CustomFields
+	task, err = s.taskRepository.UpdateTaskTx(tx, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	task.Assignees, err = s.taskRepository.SetAssigneesTx(tx, task.ID, assignees)
+	if err != nil {
+		return nil, false, err
+	}
+	// Only new assignees are subscribed, so someone who stopped watching stays unsubscribed.
+	err = s.taskWatcherRepository.AddWatchersTx(tx, task.ID, addedAssignees(before, task))
+	if err != nil {
+		return nil, false, err
+	}
+	task.CustomFields, err = s.taskRepository.SetCustomFieldValuesTx(tx, task.ID, customFields)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, userId, before, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	next, err := s.continueSeriesTx(tx, before, task, userId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var mentioned []uuid.UUID
+	task.Mentions, task.UnresolvedMentions, mentioned, err = s.mentionService.SyncMentionsTx(tx, task, nil, userId, task.Description)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mentionService.NotifyMentioned(task, nil, userId, mentioned)
+	s.notifyChanges(before, task, userId)
+	if next != nil {
+		s.notifyChanges(nil, next, userId)
+	}
+
+	err = s.attachProgress(task)
 	return task, false, err
 }
+
+// PatchTask applies a JSON merge patch to the current state of the task and saves the result like UpdateTask.
+func (s *taskService) PatchTask(projectId, taskId, userId uuid.UUID, version int, patch []byte) (*models.Task, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	current, err := s.taskRepository.GetTaskById(projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+	if current.Version != version {
+		return current, false, ErrVersionConflict
+	}
+
+	task := *current
+	fields, err := utils.ApplyMergePatch(&task, patch, taskPatchFields...)
+	if err != nil {
+		return nil, false, err
+	}
+	err = utils.ValidateStructPartial(&task, fields...)
+	if err != nil {
+		return nil, false, &ValidationError{Err: err}
+	}
+
+	return s.UpdateTask(projectId, taskId, userId, version, &task)
+}
+
+func (s *taskService) GetChildren(projectId, taskId, userId uuid.UUID) ([]*models.Task, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	_, err = s.taskRepository.GetTaskById(projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	children, err := s.taskRepository.GetChildren(projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.attachProgress(children...)
+	if err != nil {
+		return nil, false, err
+	}
+	return children, false, nil
+}
+
+func (s *taskService) GetTaskTree(projectId, taskId, userId uuid.UUID) (*models.Task, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	tasks, err := s.taskRepository.GetSubtree(projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(tasks) == 0 {
+		return nil, false, sql.ErrNoRows
+	}
+
+	// The subtree is ordered by depth, so every parent is indexed before its children.
+	byId := make(map[uuid.UUID]*models.Task, len(tasks))
+	for _, t := range tasks {
+		byId[t.ID] = t
+		if t.ParentID == nil || t.ID == taskId {
+			continue
+		}
+		if parent, ok := byId[*t.ParentID]; ok {
+			parent.Children = append(parent.Children, t)
+		}
+	}
+
+	for _, t := range tasks {
+		if len(t.Children) == 0 {
+			continue
+		}
+		done := 0
+		var estimate, doneEstimate float64
+		for _, child := range t.Children {
+			estimate += child.EstimateValue()
+			if child.IsDone() {
+				done++
+				doneEstimate += child.EstimateValue()
+			}
+		}
+		t.Progress = models.NewTaskProgress(len(t.Children), done, estimate, doneEstimate)
+	}
+
+	return tasks[0], false, nil
+}
+
+// MoveTask places a task into a status column between the given neighbours.
+func (s *taskService) MoveTask(projectId, taskId, userId uuid.UUID, dto *models.MoveTaskDTO) (*models.Task, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	before, err := s.taskRepository.GetTaskByIdForUpdateTx(tx, projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	status := dto.Status
+	if status == "" {
+		status = before.Status
+	}
+	if status != before.Status && models.IsDoneStatus(status) {
+		err = s.checkBlockers(taskId)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	after := *before
+	after.Status = status
+	after.Rank, err = s.placeTaskTx(tx, before, status, dto)
+	if err != nil {
+		return nil, false, err
+	}
+
+	after.UpdatedAt = time.Now()
+	task, err := s.taskRepository.UpdateTaskTx(tx, &after)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityUpdated, userId, before, task)
+	if err != nil {
+		return nil, false, err
+	}
+
+	next, err := s.continueSeriesTx(tx, before, task, userId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.notifyChanges(before, task, userId)
+	if next != nil {
+		s.notifyChanges(nil, next, userId)
+	}
+
+	err = s.attachProgress(task)
+	return task, false, err
+}
+
+// GetBoard returns the filtered tasks of a project grouped by status in rank order.
+// Columns follow the status filter when one is given, otherwise open statuses come before done ones.
+func (s *taskService) GetBoard(projectId, userId uuid.UUID, filter *models.TaskFilter) ([]*models.BoardColumn, bool, error) {
+	if filter == nil {
+		filter = &models.TaskFilter{}
+	}
+	filter.Sort = []*models.TaskSort{{Key: models.SortByRank}}
+
+	tasks, forbidden, err := s.GetTasksForProject(projectId, userId, filter)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	var board []*models.BoardColumn
+	for _, status := range filter.Statuses {
+		if findBoardColumn(board, status) == nil {
+			board = append(board, &models.BoardColumn{Status: status, Tasks: []*models.Task{}})
+		}
+	}
+	ordered := len(board) > 0
+
+	for _, task := range tasks {
+		column := findBoardColumn(board, task.Status)
+		if column == nil {
+			column = &models.BoardColumn{Status: task.Status, Tasks: []*models.Task{}}
+			board = append(board, column)
+		}
+		column.Tasks = append(column.Tasks, task)
+	}
+
+	if !ordered {
+		sort.SliceStable(board, func(i, j int) bool {
+			iDone, jDone := models.IsDoneStatus(board[i].Status), models.IsDoneStatus(board[j].Status)
+			if iDone != jDone {
+				return jDone
+			}
+			return strings.ToLower(board[i].Status) < strings.ToLower(board[j].Status)
+		})
+	}
+	return board, false, nil
+}
+
+// notifyChanges tells new assignees about their assignment and the watchers of the task about what changed,
+// the new status above all. A created task has no before. It runs once the change is committed,
+// so a failure is only logged.
+func (s *taskService) notifyChanges(before, after *models.Task, actorId uuid.UUID) {
+	err := s.sendChangeNotifications(before, after, actorId)
+	if err != nil {
+		log.Printf("Failed to send notifications for task %s: %v", after.ID, err)
+	}
+}
+
+func (s *taskService) sendChangeNotifications(before, after *models.Task, actorId uuid.UUID) error {
+	assigned := after.Assignees
+	if before != nil {
+		assigned = addedAssignees(before, after)
+	}
+	if len(assigned) > 0 {
+		err := s.notificationService.NotifyAssigned(after, actorId, assigned)
+		if err != nil {
+			return err
+		}
+	}
+	if before == nil {
+		return nil
+	}
+
+	changes, err := models.DiffTasks(before, after)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	watchers, err := s.taskWatcherRepository.GetWatcherIds(after.ID)
+	if err != nil {
+		return err
+	}
+	if _, ok := changes["status"]; ok {
+		return s.notificationService.NotifyStatusChanged(after, before.Status, actorId, watchers)
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return s.notificationService.NotifyUpdated(after, fields, actorId, watchers)
+}
+
+// checkTaskAccess applies the membership check of taskService for services working on a single task
+// and makes sure the task belongs to the project.
+func checkTaskAccess(projectMemberRepository repository.ProjectMemberRepository, taskRepository repository.TaskRepository, projectId, taskId, userId uuid.UUID) (*models.Task, bool, error) {
+	_, err := projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	task, err := taskRepository.GetTaskById(projectId, taskId)
+	if err != nil {
+		return nil, false, err
+	}
+	return task, false, nil
+}
+
+// addedAssignees returns the assignees of after who were not assigned to before.
+func addedAssignees(before, after *models.Task) []uuid.UUID {
+	wasAssigned := make(map[uuid.UUID]bool, len(before.Assignees))
+	for _, assignee := range before.Assignees {
+		wasAssigned[assignee] = true
+	}
+	var added []uuid.UUID
+	for _, assignee := range after.Assignees {
+		if !wasAssigned[assignee] {
+			added = append(added, assignee)
+		}
+	}
+	return added
+}
+
+// CreateDueInstances creates the instances of every series whose next occurrence has come by now
+// and returns how many it created. Each series is handled in its own transaction, and one that fails
+// is logged and left for the next run so it cannot hold up the others.
+func (s *taskService) CreateDueInstances(now time.Time) (int, error) {
+	created := 0
+	failed := make([]uuid.UUID, 0)
+	for {
+		seriesId, err := s.createDueInstance(now, failed)
+		if seriesId == nil {
+			return created, err
+		}
+		if err != nil {
+			log.Printf("Failed to create the next instance of task series %s: %v", *seriesId, err)
+			failed = append(failed, *seriesId)
+			continue
+		}
+		created++
+	}
+}
+
+// createDueInstance creates the next instance of one due series, leaving out the ones that failed before.
+// It returns the series it worked on, or nil when none is due or the series could not be looked for.
+func (s *taskService) createDueInstance(now time.Time, failed []uuid.UUID) (*uuid.UUID, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	series, err := s.taskSeriesRepository.GetDueSeriesForUpdateTx(tx, now, failed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = tx.Commit()
+		}
+		return nil, err
+	}
+
+	instance, err := s.createInstanceTx(tx, series, series.CreatedBy, now)
+	if err != nil {
+		return &series.ID, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return &series.ID, err
+	}
+
+	s.notifyChanges(nil, instance, series.CreatedBy)
+	return &series.ID, nil
+}
+
+// continueSeriesTx creates the next instance of a series right away when its latest instance gets done,
+// instead of waiting for the occurrence to come. It returns the new instance, if any.
+func (s *taskService) continueSeriesTx(tx *sql.Tx, before, after *models.Task, userId uuid.UUID) (*models.Task, error) {
+	if after.SeriesID == nil || after.OccurrenceAt == nil || before.IsDone() || !after.IsDone() {
+		return nil, nil
+	}
+
+	series, err := s.taskSeriesRepository.GetSeriesByIdForUpdateTx(tx, after.ProjectID, *after.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.NextAt == nil || !after.OccurrenceAt.Equal(series.LastOccurrenceAt) {
+		return nil, nil
+	}
+
+	return s.createInstanceTx(tx, series, userId, time.Now())
+}
+
+// createInstanceTx creates the task for the next occurrence of a locked series from its template
+// and moves the series on. Occurrences that have passed by now are skipped except for the latest one,
+// so a series that was not looked after for a while does not flood the project.
+func (s *taskService) createInstanceTx(tx *sql.Tx, series *models.TaskSeries, actorId uuid.UUID, now time.Time) (*models.Task, error) {
+	rule, err := utils.ParseRRule(series.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrence, occurrences := *series.NextAt, series.Occurrences+1
+	next, ok := nextOccurrence(rule, series, occurrence, occurrences)
+	for ok && !next.After(now) {
+		occurrence, occurrences = next, occurrences+1
+		next, ok = nextOccurrence(rule, series, occurrence, occurrences)
+	}
+
+	task := &models.Task{
+		ID:           uuid.New(),
+		Title:        series.Title,
+		Description:  series.Description,
+		Status:       series.Status,
+		Priority:     series.Priority,
+		DueDate:      &occurrence,
+		ProjectID:    series.ProjectId,
+		Estimate:     series.Estimate,
+		SeriesID:     &series.ID,
+		OccurrenceAt: &occurrence,
+		CreatedBy:    series.CreatedBy,
+	}
+
+	// Members may have left the project since the series was set up.
+	assignees := make([]uuid.UUID, 0, len(series.Assignees))
+	for _, assignee := range series.Assignees {
+		_, err = s.projectMemberRepository.GetMember(series.ProjectId, assignee)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		assignees = append(assignees, assignee)
+	}
+
+	task.Rank, err = s.placeTaskTx(tx, task, task.Status, &models.MoveTaskDTO{})
+	if err != nil {
+		return nil, err
+	}
+	task, err = s.taskRepository.CreateTaskTx(tx, task)
+	if err != nil {
+		return nil, err
+	}
+	task.Assignees, err = s.taskRepository.SetAssigneesTx(tx, task.ID, assignees)
+	if err != nil {
+		return nil, err
+	}
+	err = s.taskWatcherRepository.AddWatchersTx(tx, task.ID, append([]uuid.UUID{task.CreatedBy}, task.Assignees...))
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordTaskActivityTx(s.taskActivityRepository, tx, models.ActivityCreated, actorId, nil, task)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Occurrences = occurrences
+	series.LastOccurrenceAt = occurrence
+	series.NextAt = nil
+	if ok {
+		series.NextAt = &next
+	}
+	series.UpdatedAt = time.Now()
+	_, err = s.taskSeriesRepository.UpdateSeriesTx(tx, series)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// nextOccurrence returns the occurrence of the series after the given one, which was the n-th.
+func nextOccurrence(rule *utils.RRule, series *models.TaskSeries, after time.Time, n int) (time.Time, bool) {
+	if rule.Count > 0 && n >= rule.Count {
+		return time.Time{}, false
+	}
+	return rule.After(series.StartsAt, after)
+}
+
+// placeTaskTx returns a rank that puts the task between the neighbours of the move in the status column.
+// When the neighbours are too close the column is rebalanced first.
+func (s *taskService) placeTaskTx(tx *sql.Tx, task *models.Task, status string, dto *models.MoveTaskDTO) (string, error) {
+	prev, next, err := s.neighbourRanksTx(tx, task, status, dto)
+	if err != nil {
+		return "", err
+	}
+	rank, ok := utils.RankBetween(prev, next)
+	if ok {
+		return rank, nil
+	}
+
+	ids, err := s.taskRepository.GetColumnForUpdateTx(tx, task.ProjectID, status)
+	if err != nil {
+		return "", err
+	}
+	err = s.taskRepository.SetRanksTx(tx, ids, utils.RankSequence(len(ids)))
+	if err != nil {
+		return "", err
+	}
+
+	prev, next, err = s.neighbourRanksTx(tx, task, status, dto)
+	if err != nil {
+		return "", err
+	}
+	rank, ok = utils.RankBetween(prev, next)
+	if !ok {
+		return "", ErrInvalidMove
+	}
+	return rank, nil
+}
+
+// neighbourRanksTx returns the ranks a task has to fit between, where an empty rank leaves that side open.
+// A missing neighbour is the task next to the given one, and without neighbours the task goes last.
+func (s *taskService) neighbourRanksTx(tx *sql.Tx, task *models.Task, status string, dto *models.MoveTaskDTO) (string, string, error) {
+	var prev, next string
+	if dto.AfterId != nil {
+		neighbour, err := s.boardNeighbourTx(tx, task, status, *dto.AfterId)
+		if err != nil {
+			return "", "", err
+		}
+		prev = neighbour.Rank
+	}
+	if dto.BeforeId != nil {
+		neighbour, err := s.boardNeighbourTx(tx, task, status, *dto.BeforeId)
+		if err != nil {
+			return "", "", err
+		}
+		next = neighbour.Rank
+	}
+
+	var err error
+	switch {
+	case dto.AfterId != nil && dto.BeforeId != nil:
+		if prev > next {
+			err = ErrInvalidMove
+		}
+	case dto.AfterId != nil:
+		next, err = s.taskRepository.GetRankAfterTx(tx, task.ProjectID, status, task.ID, prev)
+	case dto.BeforeId != nil:
+		prev, err = s.taskRepository.GetRankBeforeTx(tx, task.ProjectID, status, task.ID, next)
+	default:
+		prev, err = s.taskRepository.GetLastRankTx(tx, task.ProjectID, status, task.ID)
+	}
+	return prev, next, err
+}
+
+func (s *taskService) boardNeighbourTx(tx *sql.Tx, task *models.Task, status string, neighbourId uuid.UUID) (*models.Task, error) {
+	if neighbourId == task.ID {
+		return nil, ErrInvalidMove
+	}
+	neighbour, err := s.taskRepository.GetTaskByIdForUpdateTx(tx, task.ProjectID, neighbourId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidMove
+		}
+		return nil, err
+	}
+	if neighbour.Status != status {
+		return nil, ErrInvalidMove
+	}
+	return neighbour, nil
+}
+
+func sameId(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func findBoardColumn(board []*models.BoardColumn, status string) *models.BoardColumn {
+	for _, column := range board {
+		if strings.EqualFold(column.Status, status) {
+			return column
+		}
+	}
+	return nil
+}
+
+// validateParent makes sure the parent lives in the same project, is not the task itself or one
+// of its descendants, and that the resulting hierarchy stays within models.MaxTaskDepth.
+func (s *taskService) validateParent(task *models.Task) error {
+	if task.ParentID == nil {
+		return nil
+	}
+
+	_, err := s.taskRepository.GetTaskById(task.ProjectID, *task.ParentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrParentNotFound
+		}
+		return err
+	}
+
+	ancestors, err := s.taskRepository.GetAncestorIds(*task.ParentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ancestors {
+		if id == task.ID {
+			return ErrTaskCycle
+		}
+	}
+
+	height, err := s.taskRepository.GetSubtreeHeight(task.ID)
+	if err != nil {
+		return err
+	}
+	if height == 0 {
+		height = 1
+	}
+	if len(ancestors)+height > models.MaxTaskDepth {
+		return ErrMaxDepthExceeded
+	}
+	return nil
+}
+
+func (s *taskService) validateAssignees(task *models.Task) error {
+	for _, assignee := range task.Assignees {
+		_, err := s.projectMemberRepository.GetMember(task.ProjectID, assignee)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrAssigneeNotMember
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *taskService) validateMilestone(task *models.Task) error {
+	if task.MilestoneID == nil {
+		return nil
+	}
+	_, err := s.milestoneRepository.GetMilestoneById(task.ProjectID, *task.MilestoneID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMilestoneNotFound
+	}
+	return err
+}
+
+func (s *taskService) validateSprint(task *models.Task) error {
+	if task.SprintID == nil {
+		return nil
+	}
+	sprint, err := s.sprintRepository.GetSprintById(task.ProjectID, *task.SprintID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSprintNotFound
+		}
+		return err
+	}
+	if sprint.IsClosed() {
+		return ErrSprintClosed
+	}
+	return nil
+}
+
+// validateCustomFields checks the task's custom field values against the fields of its project.
+// A null value is dropped, which clears the field.
+func (s *taskService) validateCustomFields(task *models.Task) error {
+	if len(task.CustomFields) == 0 {
+		return nil
+	}
+
+	fields, err := s.customFieldRepository.GetCustomFields(task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	isMember := func(userId uuid.UUID) (bool, error) {
+		_, err := s.projectMemberRepository.GetMember(task.ProjectID, userId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	for fieldId, value := range task.CustomFields {
+		if string(value) == "null" {
+			delete(task.CustomFields, fieldId)
+			continue
+		}
+		field := findCustomField(fields, fieldId)
+		if field == nil {
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidCustomFieldValue, fieldId)
+		}
+		err = validateCustomFieldValue(field, value, isMember)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveFilter looks up the custom fields a task list is filtered or sorted by.
+func (s *taskService) resolveFilter(projectId uuid.UUID, filter *models.TaskFilter) error {
+	if filter == nil || (len(filter.CustomFields) == 0 && len(filter.Sort) == 0) {
+		return nil
+	}
+
+	fields, err := s.customFieldRepository.GetCustomFields(projectId)
+	if err != nil {
+		return err
+	}
+
+	for _, condition := range filter.CustomFields {
+		condition.Field = findCustomField(fields, condition.FieldId)
+		if condition.Field == nil {
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidTaskFilter, condition.FieldId)
+		}
+		if condition.Operator != models.CustomFieldEquals && !condition.Field.Type.IsRanged() {
+			return fmt.Errorf("%w: %s cannot be filtered by range", ErrInvalidTaskFilter, condition.Field.Name)
+		}
+		err = validateCustomFieldFilterValue(condition.Field, condition.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, order := range filter.Sort {
+		if order.Key != models.SortByCustomField {
+			continue
+		}
+		order.CustomField = findCustomField(fields, order.CustomFieldId)
+		if order.CustomField == nil {
+			return fmt.Errorf("%w: unknown field %s", ErrInvalidTaskFilter, order.CustomFieldId)
+		}
+	}
+	return nil
+}
+
+func (s *taskService) checkBlockers(taskId uuid.UUID) error {
+	blockers, err := s.taskDependencyRepository.GetBlockers(taskId)
+	if err != nil {
+		return err
+	}
+
+	var unfinished []*models.TaskLink
+	for _, blocker := range blockers {
+		if !blocker.IsDone() {
+			unfinished = append(unfinished, blocker)
+		}
+	}
+	if len(unfinished) > 0 {
+		return &UnfinishedBlockersError{Blockers: unfinished}
+	}
+	return nil
+}
+
+func (s *taskService) attachProgress(tasks ...*models.Task) error {
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+
+	progress, err := s.taskRepository.GetChildrenProgress(ids)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		t.Progress = progress[t.ID]
+	}
+	return nil
+}
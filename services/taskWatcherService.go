@@ -0,0 +1,58 @@
+package services
+
+import (
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+)
+
+type TaskWatcherService interface {
+	Watch(projectId, taskId, userId uuid.UUID) (*models.TaskWatcher, bool, error)
+	Unwatch(projectId, taskId, userId uuid.UUID) (bool, error)
+	GetWatchers(projectId, taskId, userId uuid.UUID) ([]*models.TaskWatcher, bool, error)
+}
+
+type taskWatcherService struct {
+	taskWatcherRepository   repository.TaskWatcherRepository
+	taskRepository          repository.TaskRepository
+	projectMemberRepository repository.ProjectMemberRepository
+}
+
+func NewTaskWatcherService(taskWatcherRepository repository.TaskWatcherRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository) TaskWatcherService {
+	return &taskWatcherService{
+		taskWatcherRepository:   taskWatcherRepository,
+		taskRepository:          taskRepository,
+		projectMemberRepository: projectMemberRepository,
+	}
+}
+
+// Watch subscribes the user to the changes of the task.
+func (s *taskWatcherService) Watch(projectId, taskId, userId uuid.UUID) (*models.TaskWatcher, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	watcher, err := s.taskWatcherRepository.AddWatcher(taskId, userId)
+	return watcher, false, err
+}
+
+// Unwatch stops the notifications about the task, also for its creator and assignees.
+func (s *taskWatcherService) Unwatch(projectId, taskId, userId uuid.UUID) (bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	return false, s.taskWatcherRepository.DeleteWatcher(taskId, userId)
+}
+
+func (s *taskWatcherService) GetWatchers(projectId, taskId, userId uuid.UUID) ([]*models.TaskWatcher, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	watchers, err := s.taskWatcherRepository.GetWatchers(taskId)
+	return watchers, false, err
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/drTragger/MykroTask/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrWorkLogNotFound = errors.New("work log not found on this task")
+	ErrWorkLogRunning  = errors.New("a running timer can only be stopped or deleted")
+	ErrTimerRunning    = errors.New("another timer is already running")
+	ErrNoTimer         = errors.New("no timer is running")
+)
+
+type WorkLogService interface {
+	CreateWorkLog(projectId, taskId, userId uuid.UUID, dto *models.WorkLogDTO) (*models.WorkLog, bool, error)
+	GetWorkLogs(projectId, taskId, userId uuid.UUID) ([]*models.WorkLog, bool, error)
+	UpdateWorkLog(projectId, taskId, workLogId, userId uuid.UUID, dto *models.WorkLogDTO) (*models.WorkLog, bool, error)
+	DeleteWorkLog(projectId, taskId, workLogId, userId uuid.UUID) (bool, error)
+	StartTimer(projectId, taskId, userId uuid.UUID, dto *models.TimerDTO) (*models.WorkLog, bool, error)
+	GetTimer(userId uuid.UUID) (*models.WorkLog, error)
+	StopTimer(userId uuid.UUID) (*models.WorkLog, error)
+	GetProjectTimeReport(projectId, userId uuid.UUID, query *models.TimeReportQuery) (*models.TimeReport, bool, error)
+	GetUserTimeReport(userId uuid.UUID, query *models.TimeReportQuery) (*models.TimeReport, error)
+}
+
+type workLogService struct {
+	workLogRepository       repository.WorkLogRepository
+	taskRepository          repository.TaskRepository
+	projectMemberRepository repository.ProjectMemberRepository
+}
+
+func NewWorkLogService(workLogRepository repository.WorkLogRepository, taskRepository repository.TaskRepository, projectMemberRepository repository.ProjectMemberRepository) WorkLogService {
+	return &workLogService{
+		workLogRepository:       workLogRepository,
+		taskRepository:          taskRepository,
+		projectMemberRepository: projectMemberRepository,
+	}
+}
+
+func (s *workLogService) CreateWorkLog(projectId, taskId, userId uuid.UUID, dto *models.WorkLogDTO) (*models.WorkLog, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	workLog, err := s.workLogRepository.CreateWorkLog(&models.WorkLog{
+		ID:        uuid.New(),
+		TaskId:    taskId,
+		ProjectId: projectId,
+		UserId:    userId,
+		StartedAt: dto.StartedAt,
+		Duration:  &dto.Duration,
+		Note:      dto.Note,
+	})
+	return workLog, false, err
+}
+
+func (s *workLogService) GetWorkLogs(projectId, taskId, userId uuid.UUID) ([]*models.WorkLog, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	workLogs, err := s.workLogRepository.GetWorkLogs(taskId)
+	return workLogs, false, err
+}
+
+// UpdateWorkLog changes a finished work log of the user, work logs of others are read-only.
+func (s *workLogService) UpdateWorkLog(projectId, taskId, workLogId, userId uuid.UUID, dto *models.WorkLogDTO) (*models.WorkLog, bool, error) {
+	workLog, forbidden, err := s.getOwnWorkLog(projectId, taskId, workLogId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+	if workLog.Running {
+		return nil, false, ErrWorkLogRunning
+	}
+
+	workLog.StartedAt = dto.StartedAt
+	workLog.Duration = &dto.Duration
+	workLog.Note = dto.Note
+	workLog.UpdatedAt = time.Now()
+	workLog, err = s.workLogRepository.UpdateWorkLog(workLog)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The timer was started again in between or the work log is gone.
+		return nil, false, ErrWorkLogRunning
+	}
+	return workLog, false, err
+}
+
+// DeleteWorkLog removes a work log of the user, a running timer is discarded.
+func (s *workLogService) DeleteWorkLog(projectId, taskId, workLogId, userId uuid.UUID) (bool, error) {
+	_, forbidden, err := s.getOwnWorkLog(projectId, taskId, workLogId, userId)
+	if err != nil || forbidden {
+		return forbidden, err
+	}
+
+	err = s.workLogRepository.DeleteWorkLog(taskId, workLogId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrWorkLogNotFound
+	}
+	return false, err
+}
+
+// StartTimer starts logging time on the task from now. A user runs one timer at a time.
+func (s *workLogService) StartTimer(projectId, taskId, userId uuid.UUID, dto *models.TimerDTO) (*models.WorkLog, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	workLog, err := s.workLogRepository.CreateWorkLog(&models.WorkLog{
+		ID:        uuid.New(),
+		TaskId:    taskId,
+		ProjectId: projectId,
+		UserId:    userId,
+		StartedAt: time.Now(),
+		Note:      dto.Note,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			err = ErrTimerRunning
+		}
+		return nil, false, err
+	}
+	return workLog, false, nil
+}
+
+func (s *workLogService) GetTimer(userId uuid.UUID) (*models.WorkLog, error) {
+	workLog, err := s.workLogRepository.GetRunningTimer(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoTimer
+	}
+	return workLog, err
+}
+
+// StopTimer stops the running timer of the user, which stays as a work log of the time spent.
+func (s *workLogService) StopTimer(userId uuid.UUID) (*models.WorkLog, error) {
+	workLog, err := s.workLogRepository.StopTimer(userId, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoTimer
+	}
+	return workLog, err
+}
+
+func (s *workLogService) GetProjectTimeReport(projectId, userId uuid.UUID, query *models.TimeReportQuery) (*models.TimeReport, bool, error) {
+	_, err := s.projectMemberRepository.GetMember(projectId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+
+	rows, err := s.workLogRepository.GetProjectTimeReport(projectId, query)
+	if err != nil {
+		return nil, false, err
+	}
+	return newTimeReport(query, rows), false, nil
+}
+
+// GetUserTimeReport totals the time logged in each project of the user, by all members.
+func (s *workLogService) GetUserTimeReport(userId uuid.UUID, query *models.TimeReportQuery) (*models.TimeReport, error) {
+	rows, err := s.workLogRepository.GetUserTimeReport(userId, query)
+	if err != nil {
+		return nil, err
+	}
+	return newTimeReport(query, rows), nil
+}
+
+func (s *workLogService) getOwnWorkLog(projectId, taskId, workLogId, userId uuid.UUID) (*models.WorkLog, bool, error) {
+	_, forbidden, err := checkTaskAccess(s.projectMemberRepository, s.taskRepository, projectId, taskId, userId)
+	if err != nil || forbidden {
+		return nil, forbidden, err
+	}
+
+	workLog, err := s.workLogRepository.GetWorkLogById(taskId, workLogId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrWorkLogNotFound
+		}
+		return nil, false, err
+	}
+	if workLog.UserId != userId {
+		return nil, true, nil
+	}
+	return workLog, false, nil
+}
+
+func newTimeReport(query *models.TimeReportQuery, rows []*models.TimeReportRow) *models.TimeReport {
+	report := &models.TimeReport{GroupBy: query.GroupBy, Rows: rows}
+	if query.Range.From != nil {
+		from := query.Range.From.Format(models.DateLayout)
+		report.From = &from
+	}
+	if query.Range.To != nil {
+		to := query.Range.To.Format(models.DateLayout)
+		report.To = &to
+	}
+	for _, row := range rows {
+		report.Duration += row.Duration
+	}
+	report.Hours = models.MinutesToHours(report.Duration)
+	return report
+}
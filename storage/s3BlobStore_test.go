@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 keeps objects in memory and rejects requests whose SigV4 signature does not match
+// the request as it arrived on the wire.
+type fakeS3 struct {
+	cfg S3Config
+
+	mu           sync.Mutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+	paths        []string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, BlobStore) {
+	fake := &fakeS3{
+		cfg: S3Config{
+			Region:          "eu-central-1",
+			Bucket:          "attachments",
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		},
+		objects:      make(map[string][]byte),
+		contentTypes: make(map[string]string),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	fake.cfg.Endpoint = server.URL + "/"
+	store, err := NewS3BlobStore(fake.cfg)
+	if err != nil {
+		t.Fatalf("NewS3BlobStore returned %v", err)
+	}
+	return fake, store
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := f.verify(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + f.cfg.Bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+	if key == "broken" {
+		http.Error(w, "InternalError", http.StatusInternalServerError)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, strings.SplitN(r.RequestURI, "?", 2)[0])
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.contentTypes[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		if _, ok := f.objects[key]; !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verify recomputes the signature from the raw request URI, so a path that was escaped
+// differently from what was signed is caught.
+func (f *fakeS3) verify(r *http.Request) error {
+	var credential, signedHeaders, signature string
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return errors.New("missing authorization")
+	}
+	for _, part := range strings.Split(auth, ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			signedHeaders = value
+		case "Signature":
+			signature = value
+		}
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if len(amzDate) < 8 {
+		return errors.New("missing X-Amz-Date")
+	}
+	scope := amzDate[:8] + "/" + f.cfg.Region + "/s3/aws4_request"
+	if credential != f.cfg.AccessKeyID+"/"+scope {
+		return fmt.Errorf("unexpected credential %q", credential)
+	}
+
+	names := strings.Split(signedHeaders, ";")
+	if !sort.StringsAreSorted(names) {
+		return fmt.Errorf("signed headers %q are not sorted", signedHeaders)
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+
+	path, query, _ := strings.Cut(r.RequestURI, "?")
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		path,
+		query,
+		canonicalHeaders.String(),
+		signedHeaders,
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+f.cfg.SecretAccessKey), amzDate[:8])
+	for _, part := range []string{f.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	if want := hex.EncodeToString(hmacSHA256(key, stringToSign)); signature != want {
+		return fmt.Errorf("signature mismatch for %s %s", r.Method, path)
+	}
+	return nil
+}
+
+func TestS3BlobStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		escapedPath string
+		content     string
+		contentType string
+	}{
+		{name: "plain key", key: "projects/1/report.pdf", escapedPath: "/attachments/projects/1/report.pdf", content: "%PDF-1.7", contentType: "application/pdf"},
+		{name: "spaces and reserved characters", key: "projects/1/Q1 report+final (v2).txt", escapedPath: "/attachments/projects/1/Q1%20report%2Bfinal%20%28v2%29.txt", content: "hello", contentType: "text/plain"},
+		{name: "unicode", key: "projects/1/звіт.txt", escapedPath: "/attachments/projects/1/%D0%B7%D0%B2%D1%96%D1%82.txt", content: "привіт"},
+		{name: "empty content", key: "projects/1/empty", escapedPath: "/attachments/projects/1/empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, store := newFakeS3(t)
+
+			err := store.Put(tt.key, strings.NewReader(tt.content), int64(len(tt.content)), tt.contentType)
+			if err != nil {
+				t.Fatalf("Put returned %v", err)
+			}
+			if got := fake.contentTypes[tt.key]; got != tt.contentType {
+				t.Errorf("stored content type %q, want %q", got, tt.contentType)
+			}
+
+			r, err := store.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get returned %v", err)
+			}
+			got, err := io.ReadAll(r)
+			r.Close()
+			if err != nil || !bytes.Equal(got, []byte(tt.content)) {
+				t.Fatalf("Get returned %q, %v, want %q", got, err, tt.content)
+			}
+
+			err = store.Delete(tt.key)
+			if err != nil {
+				t.Fatalf("Delete returned %v", err)
+			}
+			_, err = store.Get(tt.key)
+			if !errors.Is(err, ErrBlobNotFound) {
+				t.Fatalf("Get after Delete returned %v, want ErrBlobNotFound", err)
+			}
+
+			for _, path := range fake.paths {
+				if path != tt.escapedPath {
+					t.Errorf("request path %q, want %q", path, tt.escapedPath)
+				}
+			}
+		})
+	}
+}
+
+func TestS3BlobStoreErrors(t *testing.T) {
+	_, store := newFakeS3(t)
+
+	_, err := store.Get("projects/1/missing")
+	if !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get of a missing key returned %v, want ErrBlobNotFound", err)
+	}
+
+	err = store.Delete("projects/1/missing")
+	if err != nil {
+		t.Errorf("Delete of a missing key returned %v, want nil", err)
+	}
+
+	err = store.Put("broken", strings.NewReader("x"), 1, "")
+	if err == nil || errors.Is(err, ErrBlobNotFound) || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Put on a failing server returned %v, want the server error", err)
+	}
+}
+
+func TestNewS3BlobStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     S3Config
+		wantErr bool
+	}{
+		{name: "endpoint and bucket", cfg: S3Config{Endpoint: "http://localhost:9000", Bucket: "attachments"}},
+		{name: "missing endpoint", cfg: S3Config{Bucket: "attachments"}, wantErr: true},
+		{name: "missing bucket", cfg: S3Config{Endpoint: "http://localhost:9000"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewS3BlobStore(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewS3BlobStore returned %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && store.(*s3BlobStore).cfg.Region != "us-east-1" {
+				t.Errorf("default region %q, want us-east-1", store.(*s3BlobStore).cfg.Region)
+			}
+		})
+	}
+}
+
+func TestURIEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "report.pdf", want: "report.pdf"},
+		{in: "AZaz09-_.~", want: "AZaz09-_.~"},
+		{in: "a b", want: "a%20b"},
+		{in: "a+b=c&d", want: "a%2Bb%3Dc%26d"},
+		{in: "a/b", want: "a%2Fb"},
+		{in: "100%", want: "100%25"},
+		{in: "ü", want: "%C3%BC"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := uriEscape(tt.in); got != tt.want {
+			t.Errorf("uriEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// WriteCSVResponse sends the records as a CSV file download, the first record being the header.
+// Cells that a spreadsheet would run as a formula are prefixed with a quote.
+func WriteCSVResponse(w http.ResponseWriter, fileName string, records [][]string) error {
+	for _, record := range records {
+		for i, cell := range record {
+			if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+				record[i] = "'" + cell
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	err := writer.WriteAll(records)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
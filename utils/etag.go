@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidETag = errors.New("invalid entity tag")
+	ErrWeakETag    = errors.New("weak entity tags cannot be used for conditional updates")
+)
+
+func FormatETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ParseETag reads the version out of an If-Match header value. A well-formed weak tag gives ErrWeakETag,
+// as If-Match only matches strong tags.
+func ParseETag(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	weak := strings.HasPrefix(value, "W/")
+	value = strings.TrimPrefix(value, "W/")
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, ErrInvalidETag
+	}
+
+	version, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || version < 1 {
+		return 0, ErrInvalidETag
+	}
+	if weak {
+		return 0, ErrWeakETag
+	}
+	return version, nil
+}
+
+// ReadIfMatch returns the version a conditional update is based on. Updates must send If-Match,
+// so a missing header is answered with 428 Precondition Required. A weak tag never matches
+// and is answered with 412 Precondition Failed.
+func ReadIfMatch(r *http.Request) (int, int, *ErrorResponse) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, http.StatusPreconditionRequired, &ErrorResponse{
+			Status:  false,
+			Message: "Missing If-Match header.",
+		}
+	}
+
+	version, err := ParseETag(header)
+	if errors.Is(err, ErrWeakETag) {
+		return 0, http.StatusPreconditionFailed, &ErrorResponse{
+			Status:  false,
+			Message: "If-Match requires a strong entity tag.",
+			Errors:  err.Error(),
+		}
+	}
+	if err != nil {
+		return 0, http.StatusBadRequest, &ErrorResponse{
+			Status:  false,
+			Message: "Invalid If-Match header.",
+			Errors:  err.Error(),
+		}
+	}
+	return version, 0, nil
+}
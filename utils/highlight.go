@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"github.com/drTragger/MykroTask/models"
+	"html"
+	"strings"
+)
+
+// EscapeHighlight HTML escapes a search snippet while keeping the tags that mark its matched words.
+func EscapeHighlight(snippet string) string {
+	var b strings.Builder
+	for _, part := range strings.SplitAfter(snippet, models.HighlightStop) {
+		text, highlighted, found := strings.Cut(strings.TrimSuffix(part, models.HighlightStop), models.HighlightStart)
+		b.WriteString(html.EscapeString(text))
+		if found {
+			b.WriteString(models.HighlightStart + html.EscapeString(highlighted) + models.HighlightStop)
+		}
+	}
+	return b.String()
+}
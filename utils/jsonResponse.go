@@ -2,6 +2,8 @@ package utils
 
 import (
 	"encoding/json"
+	"io"
+	"mime"
 	"net/http"
 )
 
@@ -28,6 +30,7 @@ type ErrorResponse struct {
 	Status  bool        `json:"status"`
 	Message string      `json:"message,omitempty"`
 	Errors  interface{} `json:"errors,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 func (er *ErrorResponse) GetStatus() bool {
@@ -56,3 +59,24 @@ func UnmarshalRequest(r *http.Request, v interface{}) *ErrorResponse {
 	}
 	return nil
 }
+
+// ReadMergePatch reads an RFC 7396 merge patch body. Plain application/json is accepted as well.
+func ReadMergePatch(r *http.Request) ([]byte, int, *ErrorResponse) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+		return nil, http.StatusUnsupportedMediaType, &ErrorResponse{
+			Status:  false,
+			Message: "Content-Type must be application/merge-patch+json.",
+		}
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, http.StatusBadRequest, &ErrorResponse{
+			Status:  false,
+			Message: "Failed to read body data",
+			Errors:  err.Error(),
+		}
+	}
+	return patch, 0, nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A handle must not be preceded by a word character, so e-mail addresses are not picked up as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// ExtractMentions returns the distinct @handles found in text, lower-cased and in order of appearance.
+func ExtractMentions(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], "._-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
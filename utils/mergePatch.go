@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrInvalidMergePatch = errors.New("invalid merge patch")
+
+// ApplyMergePatch applies an RFC 7396 JSON merge patch to the struct pointed to by target.
+// Only the top-level JSON keys listed in allowed may be patched; a null value clears the field.
+// It returns the Go names of the patched fields so they can be validated on their own.
+func ApplyMergePatch(target interface{}, patch []byte, allowed ...string) ([]string, error) {
+	var patchValue interface{}
+	err := json.Unmarshal(patch, &patchValue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMergePatch, err)
+	}
+	patchObject, ok := patchValue.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: patch must be a JSON object", ErrInvalidMergePatch)
+	}
+
+	fieldNames := jsonFieldNames(reflect.TypeOf(target).Elem())
+	var fields []string
+	for key := range patchObject {
+		if !contains(allowed, key) {
+			return nil, fmt.Errorf("%w: field %q cannot be patched", ErrInvalidMergePatch, key)
+		}
+		fields = append(fields, fieldNames[key])
+	}
+
+	document, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	var documentValue interface{}
+	err = json.Unmarshal(document, &documentValue)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := json.Marshal(mergePatch(documentValue, patchObject))
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into a zeroed value so that removed keys end up as zero values instead of keeping the old ones.
+	value := reflect.ValueOf(target).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	err = json.Unmarshal(merged, target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMergePatch, err)
+	}
+	return fields, nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+func jsonFieldNames(t reflect.Type) map[string]string {
+	names := make(map[string]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = field.Name
+	}
+	return names
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Ranks are base 36 fractions compared byte by byte, so there is always room between two of them
+// as long as they may grow longer. A rank never ends with a zero digit.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// MaxRankLength is how long a rank may grow before its column has to be rebalanced.
+const MaxRankLength = 16
+
+// RankBetween returns a rank that sorts after prev and before next, where an empty prev or next
+// leaves that side open. It reports false when the two ranks are too close to fit another one.
+func RankBetween(prev, next string) (string, bool) {
+	if next != "" && prev >= next {
+		return "", false
+	}
+
+	var b strings.Builder
+	bounded := next != ""
+	for i := 0; i < MaxRankLength; i++ {
+		low, high := rankDigit(prev, i), len(rankDigits)
+		if bounded {
+			high = rankDigit(next, i)
+		}
+		if low == high {
+			b.WriteByte(rankDigits[low])
+			continue
+		}
+		if high-low > 1 {
+			b.WriteByte(rankDigits[(low+high)/2])
+			return b.String(), true
+		}
+		// The digits are adjacent, so anything after prev's digit stays below next.
+		b.WriteByte(rankDigits[low])
+		bounded = false
+	}
+	return "", false
+}
+
+// RankSequence returns n ascending ranks spread evenly, used to rebalance a column.
+func RankSequence(n int) []string {
+	width, space := 1, len(rankDigits)
+	for space/(n+1) < len(rankDigits) {
+		width++
+		space *= len(rankDigits)
+	}
+
+	step := space / (n + 1)
+	ranks := make([]string, n)
+	for i := range ranks {
+		rank := strconv.FormatInt(int64((i+1)*step), len(rankDigits))
+		rank = strings.Repeat("0", width-len(rank)) + rank
+		ranks[i] = strings.TrimRight(rank, "0")
+	}
+	return ranks
+}
+
+// rankDigit returns the value of the i-th digit of a rank, reading missing digits as zero.
+func rankDigit(rank string, i int) int {
+	if i >= len(rank) {
+		return 0
+	}
+	digit := strings.IndexByte(rankDigits, rank[i])
+	if digit < 0 {
+		return 0
+	}
+	return digit
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		want       string
+		ok         bool
+	}{
+		{name: "empty column", want: "i", ok: true},
+		{name: "after the last rank", prev: "i", want: "r", ok: true},
+		{name: "before the first rank", next: "i", want: "9", ok: true},
+		{name: "room in the first digit", prev: "a", next: "k", want: "f", ok: true},
+		{name: "adjacent digits grow a digit", prev: "a", next: "b", want: "ai", ok: true},
+		{name: "before a rank close to zero", next: "1", want: "0i", ok: true},
+		{name: "shared prefix", prev: "ab", next: "ad", want: "ac", ok: true},
+		{name: "last digit that fits", prev: "a", next: "a" + strings.Repeat("0", MaxRankLength-3) + "1", want: "a" + strings.Repeat("0", MaxRankLength-2) + "i", ok: true},
+		{name: "bounded exhaustion at MaxRankLength", prev: "a", next: "a" + strings.Repeat("0", MaxRankLength-2) + "1"},
+		{name: "open exhaustion at MaxRankLength", prev: strings.Repeat("z", MaxRankLength)},
+		{name: "equal bounds", prev: "i", next: "i"},
+		{name: "reversed bounds", prev: "r", next: "i"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RankBetween(tt.prev, tt.next)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("RankBetween(%q, %q) = %q, %v, want %q, %v", tt.prev, tt.next, got, ok, tt.want, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got <= tt.prev || (tt.next != "" && got >= tt.next) {
+				t.Errorf("RankBetween(%q, %q) = %q does not sort between its bounds", tt.prev, tt.next, got)
+			}
+			if len(got) > MaxRankLength || strings.HasSuffix(got, "0") {
+				t.Errorf("RankBetween(%q, %q) = %q is not a valid rank", tt.prev, tt.next, got)
+			}
+		})
+	}
+}
+
+func TestRankBetweenUntilExhausted(t *testing.T) {
+	// Inserting right after the same rank again and again has to run out at MaxRankLength, not loop forever.
+	prev, next := "a", "b"
+	for i := 0; ; i++ {
+		rank, ok := RankBetween(prev, next)
+		if !ok {
+			break
+		}
+		if i > MaxRankLength*len(rankDigits) {
+			t.Fatalf("RankBetween(%q, %q) never ran out of room", prev, next)
+		}
+		next = rank
+	}
+	if len(next) > MaxRankLength {
+		t.Errorf("rank %q is longer than MaxRankLength", next)
+	}
+}
+
+func TestRankSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{name: "empty column", n: 0, want: []string{}},
+		{name: "single task", n: 1, want: []string{"i"}},
+		{name: "two tasks", n: 2, want: []string{"c", "o"}},
+		{name: "wider than one digit", n: 36},
+		{name: "large column", n: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankSequence(tt.n)
+			if len(got) != tt.n {
+				t.Fatalf("RankSequence(%d) returned %d ranks", tt.n, len(got))
+			}
+			if tt.want != nil && strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("RankSequence(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+			for i, rank := range got {
+				if rank == "" || len(rank) > MaxRankLength || strings.HasSuffix(rank, "0") {
+					t.Fatalf("RankSequence(%d)[%d] = %q is not a valid rank", tt.n, i, rank)
+				}
+				if i > 0 && got[i-1] >= rank {
+					t.Fatalf("RankSequence(%d) is not ascending at %d: %q >= %q", tt.n, i, got[i-1], rank)
+				}
+				// A rebalanced column must leave room on both sides of every task.
+				prev := ""
+				if i > 0 {
+					prev = got[i-1]
+				}
+				if _, ok := RankBetween(prev, rank); !ok {
+					t.Fatalf("RankSequence(%d) left no room between %q and %q", tt.n, prev, rank)
+				}
+			}
+			if len(got) > 0 {
+				if _, ok := RankBetween(got[len(got)-1], ""); !ok {
+					t.Fatalf("RankSequence(%d) left no room after %q", tt.n, got[len(got)-1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RRuleFrequency is the FREQ of a recurrence rule.
+type RRuleFrequency string
+
+const (
+	RRuleDaily   RRuleFrequency = "DAILY"
+	RRuleWeekly  RRuleFrequency = "WEEKLY"
+	RRuleMonthly RRuleFrequency = "MONTHLY"
+	RRuleYearly  RRuleFrequency = "YEARLY"
+)
+
+// maxRRuleInterval keeps the period arithmetic of a rule within sane bounds.
+const maxRRuleInterval = 1000
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RRule is the subset of an RFC 5545 recurrence rule that tasks use: FREQ, INTERVAL, COUNT, UNTIL,
+// BYDAY without ordinals and BYMONTHDAY. Weeks start on Monday.
+type RRule struct {
+	Freq       RRuleFrequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// ParseRRule reads a rule like "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", with or without the "RRULE:" prefix.
+func ParseRRule(value string) (*RRule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	rule := &RRule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = RRuleFrequency(val)
+			if rule.Freq != RRuleDaily && rule.Freq != RRuleWeekly && rule.Freq != RRuleMonthly && rule.Freq != RRuleYearly {
+				return nil, fmt.Errorf("unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+			if err != nil || rule.Interval < 1 || rule.Interval > maxRRuleInterval {
+				return nil, fmt.Errorf("INTERVAL must be between 1 and %d", maxRRuleInterval)
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+			if err != nil || rule.Count < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive number")
+			}
+		case "UNTIL":
+			rule.Until, err = parseRRuleTime(val)
+			if err != nil {
+				return nil, err
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, monthDay)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be used together")
+	}
+	return rule, nil
+}
+
+func parseRRuleTime(value string) (*time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			if layout == "20060102" {
+				// A date includes the whole day.
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("UNTIL must look like 20240131 or 20240131T090000Z")
+}
+
+// String returns the rule in its canonical form.
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			days = append(days, strings.ToUpper(weekday.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, monthDay := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(monthDay))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// After returns the first occurrence of the series starting at start that comes after t,
+// or false when the rule ends before that. COUNT is left to the caller, who knows how many occurrences there were.
+// Occurrences keep the time of day of start, in UTC.
+func (r *RRule) After(start, t time.Time) (time.Time, bool) {
+	start = start.UTC()
+	first := r.firstPeriod(start, t)
+	// Rules like the 30th of February never match, give up well before looping forever.
+	for k := first; k <= first+4*maxRRuleInterval; k++ {
+		candidates := r.period(start, k)
+		if len(candidates) > 0 && r.Until != nil && candidates[0].After(*r.Until) {
+			return time.Time{}, false
+		}
+		for _, candidate := range candidates {
+			if candidate.Before(start) || !candidate.After(t) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// firstPeriod estimates the period holding t, starting a little early so no occurrence is missed.
+func (r *RRule) firstPeriod(start, t time.Time) int {
+	if !t.After(start) {
+		return 0
+	}
+	var periods int
+	switch r.Freq {
+	case RRuleDaily:
+		periods = int(t.Sub(start).Hours() / 24)
+	case RRuleWeekly:
+		periods = int(t.Sub(start).Hours() / (24 * 7))
+	case RRuleMonthly:
+		periods = (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+	case RRuleYearly:
+		periods = t.Year() - start.Year()
+	}
+	k := periods/r.Interval - 1
+	if k < 0 {
+		return 0
+	}
+	return k
+}
+
+// period lists the occurrences of the k-th period of the rule in order.
+func (r *RRule) period(start time.Time, k int) []time.Time {
+	hour, minute, second := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.UTC)
+	}
+
+	var candidates []time.Time
+	switch r.Freq {
+	case RRuleDaily:
+		day := start.AddDate(0, 0, k*r.Interval)
+		if r.matchesDay(day) {
+			candidates = append(candidates, day)
+		}
+	case RRuleWeekly:
+		weekStart := start.AddDate(0, 0, -daysSinceMonday(start.Weekday())+k*r.Interval*7)
+		weekdays := r.ByDay
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		for _, weekday := range weekdays {
+			candidates = append(candidates, weekStart.AddDate(0, 0, daysSinceMonday(weekday)))
+		}
+	case RRuleMonthly:
+		first := time.Date(start.Year(), start.Month()+time.Month(k*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+		for day := 1; day <= daysIn(first.Year(), first.Month()); day++ {
+			candidate := at(first.Year(), first.Month(), day)
+			if r.matchesMonthDay(candidate, start) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	case RRuleYearly:
+		year := start.Year() + k*r.Interval
+		for day := 1; day <= daysIn(year, start.Month()); day++ {
+			candidate := at(year, start.Month(), day)
+			if r.matchesMonthDay(candidate, start) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Before(candidates[j])
+	})
+	return candidates
+}
+
+// matchesMonthDay applies BYMONTHDAY and BYDAY within a month, or the day of the start when neither is given.
+func (r *RRule) matchesMonthDay(day, start time.Time) bool {
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		return day.Day() == start.Day()
+	}
+	return r.matchesDay(day)
+}
+
+func (r *RRule) matchesDay(day time.Time) bool {
+	if len(r.ByDay) > 0 {
+		found := false
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.ByMonthDay) > 0 {
+		last := daysIn(day.Year(), day.Month())
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay < 0 {
+				monthDay += last + 1
+			}
+			if day.Day() == monthDay {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "daily", value: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix and lower case", value: " rrule:freq=weekly;byday=mo,fr ", want: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{name: "default interval is dropped", value: "FREQ=DAILY;INTERVAL=1", want: "FREQ=DAILY"},
+		{name: "interval", value: "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE"},
+		{name: "count", value: "FREQ=MONTHLY;COUNT=3;BYMONTHDAY=1,-1", want: "FREQ=MONTHLY;COUNT=3;BYMONTHDAY=1,-1"},
+		{name: "until date includes the whole day", value: "FREQ=DAILY;UNTIL=20240131", want: "FREQ=DAILY;UNTIL=20240131T235959Z"},
+		{name: "until time", value: "FREQ=DAILY;UNTIL=20240131T090000Z", want: "FREQ=DAILY;UNTIL=20240131T090000Z"},
+		{name: "empty", value: "", wantErr: true},
+		{name: "missing FREQ", value: "INTERVAL=2", wantErr: true},
+		{name: "part without value", value: "FREQ", wantErr: true},
+		{name: "unsupported FREQ", value: "FREQ=HOURLY", wantErr: true},
+		{name: "repeated part", value: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "unsupported part", value: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "zero interval", value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "interval too large", value: "FREQ=DAILY;INTERVAL=1001", wantErr: true},
+		{name: "zero count", value: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{name: "count and until", value: "FREQ=DAILY;COUNT=2;UNTIL=20240131", wantErr: true},
+		{name: "malformed until", value: "FREQ=DAILY;UNTIL=2024-01-31", wantErr: true},
+		{name: "BYDAY with ordinal", value: "FREQ=MONTHLY;BYDAY=1MO", wantErr: true},
+		{name: "BYMONTHDAY zero", value: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: true},
+		{name: "BYMONTHDAY out of range", value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRRule(%q) = %q, want an error", tt.value, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) returned %v", tt.value, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Fatalf("ParseRRule(%q).String() = %q, want %q", tt.value, got, tt.want)
+			}
+
+			again, err := ParseRRule(rule.String())
+			if err != nil || again.String() != tt.want {
+				t.Fatalf("canonical form %q does not parse back to itself", tt.want)
+			}
+		})
+	}
+}
+
+func TestRRuleAfter(t *testing.T) {
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	// 1 January 2024 is a Monday.
+	monday := date(2024, time.January, 1, 9)
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		t     time.Time
+		want  time.Time
+		ok    bool
+	}{
+		{name: "daily", rule: "FREQ=DAILY", start: monday, t: monday, want: date(2024, time.January, 2, 9), ok: true},
+		{name: "first occurrence is the start", rule: "FREQ=DAILY", start: monday, t: monday.AddDate(0, 0, -2), want: monday, ok: true},
+		{name: "daily interval", rule: "FREQ=DAILY;INTERVAL=3", start: monday, t: date(2024, time.January, 5, 0), want: date(2024, time.January, 7, 9), ok: true},
+		{name: "far in the future", rule: "FREQ=DAILY", start: monday, t: date(2030, time.June, 15, 12), want: date(2030, time.June, 16, 9), ok: true},
+		{name: "weekly on the start day", rule: "FREQ=WEEKLY", start: monday, t: monday, want: date(2024, time.January, 8, 9), ok: true},
+		{name: "BYDAY later that week", rule: "FREQ=WEEKLY;BYDAY=MO,FR", start: monday, t: monday, want: date(2024, time.January, 5, 9), ok: true},
+		{name: "BYDAY into the next week", rule: "FREQ=WEEKLY;BYDAY=MO,FR", start: monday, t: date(2024, time.January, 5, 9), want: date(2024, time.January, 8, 9), ok: true},
+		{name: "BYDAY before the start is skipped", rule: "FREQ=WEEKLY;BYDAY=MO,WE", start: date(2024, time.January, 3, 9), t: date(2024, time.January, 2, 0), want: date(2024, time.January, 3, 9), ok: true},
+		{name: "BYDAY every other week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE", start: monday, t: date(2024, time.January, 3, 9), want: date(2024, time.January, 17, 9), ok: true},
+		{name: "daily BYDAY skips weekends", rule: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", start: monday, t: date(2024, time.January, 5, 9), want: date(2024, time.January, 8, 9), ok: true},
+		{name: "monthly on the 31st skips short months", rule: "FREQ=MONTHLY", start: date(2024, time.January, 31, 9), t: date(2024, time.January, 31, 9), want: date(2024, time.March, 31, 9), ok: true},
+		{name: "last day of February in a leap year", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: date(2024, time.January, 31, 9), t: date(2024, time.January, 31, 9), want: date(2024, time.February, 29, 9), ok: true},
+		{name: "last day of the month rolls over", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: date(2024, time.January, 31, 9), t: date(2024, time.February, 29, 9), want: date(2024, time.March, 31, 9), ok: true},
+		{name: "last day of the year", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: date(2024, time.November, 30, 9), t: date(2024, time.December, 1, 0), want: date(2024, time.December, 31, 9), ok: true},
+		{name: "yearly on 29 February", rule: "FREQ=YEARLY", start: date(2024, time.February, 29, 9), t: date(2024, time.February, 29, 9), want: date(2028, time.February, 29, 9), ok: true},
+		{name: "COUNT is left to the caller", rule: "FREQ=DAILY;COUNT=2", start: monday, t: date(2024, time.January, 5, 9), want: date(2024, time.January, 6, 9), ok: true},
+		{name: "last occurrence before UNTIL", rule: "FREQ=DAILY;UNTIL=20240103", start: monday, t: date(2024, time.January, 2, 9), want: date(2024, time.January, 3, 9), ok: true},
+		{name: "past UNTIL", rule: "FREQ=DAILY;UNTIL=20240103", start: monday, t: date(2024, time.January, 3, 9), ok: false},
+		{name: "UNTIL between BYDAY occurrences", rule: "FREQ=WEEKLY;BYDAY=MO,FR;UNTIL=20240104T000000Z", start: monday, t: monday, ok: false},
+		{name: "rule that never matches", rule: "FREQ=YEARLY;BYMONTHDAY=30", start: date(2024, time.February, 1, 9), t: date(2024, time.February, 1, 9), ok: false},
+		{name: "start outside UTC keeps its UTC time", rule: "FREQ=DAILY", start: time.Date(2024, time.January, 1, 9, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)), t: monday, want: date(2024, time.January, 2, 7), ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) returned %v", tt.rule, err)
+			}
+			got, ok := rule.After(tt.start, tt.t)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Fatalf("After(%s, %s) = %s, %v, want %s, %v", tt.start, tt.t, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"github.com/google/uuid"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CustomFieldParamPrefix = "cf."
+	unassignedParam        = "none"
+	noMilestoneParam       = "none"
+	backlogParam           = "none"
+)
+
+// ParseTaskFilter reads the filter of a task list request from its query parameters.
+// List parameters can be repeated or comma separated, e.g.
+// ?status=todo,doing&assignee=<id>&assignee=none&dueFrom=2024-01-01&overdue=true&q=login
+// &label=<id>&labelMatch=all&milestone=<id>&sprint=none&cf.<fieldId>=value&cf.<fieldId>.from=1&sort=priority,-dueDate,cf.<fieldId>.
+func ParseTaskFilter(query url.Values) (*models.TaskFilter, error) {
+	filter := &models.TaskFilter{
+		Statuses:   listParam(query, "status"),
+		Priorities: listParam(query, "priority"),
+		Search:     strings.TrimSpace(query.Get("q")),
+		LabelMatch: models.LabelMatchAny,
+	}
+
+	for _, value := range listParam(query, "assignee") {
+		if value == unassignedParam {
+			filter.Unassigned = true
+			continue
+		}
+		assigneeId, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assignee %q", value)
+		}
+		filter.AssigneeIds = append(filter.AssigneeIds, assigneeId)
+	}
+
+	for _, value := range listParam(query, "creator") {
+		creatorId, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creator %q", value)
+		}
+		filter.CreatorIds = append(filter.CreatorIds, creatorId)
+	}
+
+	var err error
+	filter.Due, err = dateRangeParam(query, "dueFrom", "dueTo")
+	if err != nil {
+		return nil, err
+	}
+	filter.Created, err = dateRangeParam(query, "createdFrom", "createdTo")
+	if err != nil {
+		return nil, err
+	}
+	filter.Updated, err = dateRangeParam(query, "updatedFrom", "updatedTo")
+	if err != nil {
+		return nil, err
+	}
+
+	if value := query.Get("overdue"); value != "" {
+		filter.Overdue, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overdue %q", value)
+		}
+	}
+
+	for _, value := range listParam(query, "label") {
+		labelId, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label %q", value)
+		}
+		filter.LabelIds = append(filter.LabelIds, labelId)
+	}
+
+	for _, value := range listParam(query, "milestone") {
+		if value == noMilestoneParam {
+			filter.NoMilestone = true
+			continue
+		}
+		milestoneId, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid milestone %q", value)
+		}
+		filter.MilestoneIds = append(filter.MilestoneIds, milestoneId)
+	}
+
+	for _, value := range listParam(query, "sprint") {
+		if value == backlogParam {
+			filter.NoSprint = true
+			continue
+		}
+		sprintId, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sprint %q", value)
+		}
+		filter.SprintIds = append(filter.SprintIds, sprintId)
+	}
+
+	if value := query.Get("labelMatch"); value != "" {
+		match := models.LabelMatch(value)
+		if match != models.LabelMatchAny && match != models.LabelMatchAll {
+			return nil, fmt.Errorf("labelMatch must be %q or %q", models.LabelMatchAny, models.LabelMatchAll)
+		}
+		filter.LabelMatch = match
+	}
+
+	for key, values := range query {
+		if !strings.HasPrefix(key, CustomFieldParamPrefix) {
+			continue
+		}
+		condition, err := parseCustomFieldCondition(strings.TrimPrefix(key, CustomFieldParamPrefix))
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			c := *condition
+			c.Value = value
+			filter.CustomFields = append(filter.CustomFields, &c)
+		}
+	}
+
+	for _, key := range listParam(query, "sort") {
+		sort, err := parseTaskSort(key)
+		if err != nil {
+			return nil, err
+		}
+		filter.Sort = append(filter.Sort, sort)
+	}
+
+	return filter, nil
+}
+
+// listParam collects the values of a repeatable parameter whose values may also be comma separated.
+func listParam(query url.Values, key string) []string {
+	var list []string
+	for _, value := range query[key] {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+func dateRangeParam(query url.Values, fromKey, toKey string) (models.DateRange, error) {
+	from, err := dateParam(query, fromKey)
+	if err != nil {
+		return models.DateRange{}, err
+	}
+	to, err := dateParam(query, toKey)
+	if err != nil {
+		return models.DateRange{}, err
+	}
+	return models.DateRange{From: from, To: to}, nil
+}
+
+func dateParam(query url.Values, key string) (*time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date like %s", key, models.DateLayout)
+	}
+	return &date, nil
+}
+
+// parseCustomFieldCondition reads "<fieldId>", "<fieldId>.from" or "<fieldId>.to".
+func parseCustomFieldCondition(param string) (*models.CustomFieldCondition, error) {
+	fieldIdStr, operator, found := strings.Cut(param, ".")
+	condition := &models.CustomFieldCondition{Operator: models.CustomFieldEquals}
+	if found {
+		condition.Operator = models.CustomFieldOperator(operator)
+		if condition.Operator != models.CustomFieldFrom && condition.Operator != models.CustomFieldTo {
+			return nil, fmt.Errorf("unknown custom field operator %q", operator)
+		}
+	}
+
+	fieldId, err := uuid.Parse(fieldIdStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom field %q", fieldIdStr)
+	}
+	condition.FieldId = fieldId
+	return condition, nil
+}
+
+// parseTaskSort reads a sort key, descending when prefixed with a minus.
+func parseTaskSort(key string) (*models.TaskSort, error) {
+	sort := &models.TaskSort{}
+	if strings.HasPrefix(key, "-") {
+		sort.Desc = true
+		key = key[1:]
+	}
+
+	if strings.HasPrefix(key, CustomFieldParamPrefix) {
+		fieldId, err := uuid.Parse(strings.TrimPrefix(key, CustomFieldParamPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom field %q", key)
+		}
+		sort.Key = models.SortByCustomField
+		sort.CustomFieldId = fieldId
+		return sort, nil
+	}
+
+	for _, sortKey := range models.GetTaskSortKeys() {
+		if key == string(sortKey) {
+			sort.Key = sortKey
+			return sort, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown sort key %q", key)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/drTragger/MykroTask/models"
+	"net/url"
+)
+
+// ParseTimeReportQuery reads a time report request like ?from=2024-01-01&to=2024-01-31&groupBy=member.
+// The first of the given groups is the default.
+func ParseTimeReportQuery(query url.Values, groups ...models.TimeReportGroup) (*models.TimeReportQuery, error) {
+	dates, err := dateRangeParam(query, "from", "to")
+	if err != nil {
+		return nil, err
+	}
+	if dates.From != nil && dates.To != nil && dates.To.Before(*dates.From) {
+		return nil, fmt.Errorf("to must not be before from")
+	}
+
+	report := &models.TimeReportQuery{GroupBy: groups[0], Range: dates}
+	if value := query.Get("groupBy"); value != "" {
+		report.GroupBy = ""
+		for _, group := range groups {
+			if string(group) == value {
+				report.GroupBy = group
+			}
+		}
+		if report.GroupBy == "" {
+			return nil, fmt.Errorf("unknown groupBy %q", value)
+		}
+	}
+	return report, nil
+}
@@ -16,6 +16,12 @@ func init() {
 		log.Fatal(err)
 		return
 	}
+
+	err = validate.RegisterValidation("projectkey", validateProjectKey)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
 }
 
 func validateRole(fl validator.FieldLevel) bool {
@@ -29,6 +35,17 @@ func validateRole(fl validator.FieldLevel) bool {
 	return false
 }
 
+func validateProjectKey(fl validator.FieldLevel) bool {
+	return models.ProjectKeyPattern.MatchString(fl.Field().String())
+}
+
 func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
+
+func ValidateStructPartial(s interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return validate.StructPartial(s, fields...)
+}